@@ -0,0 +1,935 @@
+//
+// Analysis Utilities
+//
+// Regression and fit-quality helpers for comparing measured data
+// against the theoretical log-log slope of each system
+//
+
+package rulebook
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ErrInsufficientPoints is returned when a fit has fewer than two usable points
+var ErrInsufficientPoints = errors.New("insufficient points for fit (need at least 2)")
+
+// FitResult is an ordinary least-squares line through the actual points in
+// log-log space. SlopeStdErr is only meaningful when HasStdErr reports true.
+type FitResult struct {
+	Slope        float64 `json:"slope"`
+	Intercept    float64 `json:"intercept"`
+	RSquared     float64 `json:"rSquared"`
+	SlopeStdErr  float64 `json:"slopeStdErr"`
+	Points       int     `json:"points"`
+	MeanLogScale float64 `json:"meanLogScale"`
+	// Trimmed is the number of actual points left out by FitTrimHead and
+	// FitTrimTail
+	Trimmed int `json:"trimmed,omitempty"`
+}
+
+// FitTrimHead and FitTrimTail drop this many of the lowest- and
+// highest-iteration actual points before FitLogLog and its variants fit,
+// a standard way to exclude finite-size effects at the ends of the range.
+// Trimming that leaves fewer than two points fails with ErrInsufficientPoints.
+var (
+	FitTrimHead = 0
+	FitTrimTail = 0
+)
+
+// Rounded returns the fit with its values rounded by OutputRounding, for output
+func (f FitResult) Rounded() FitResult {
+	f.Slope = OutputRounding.Apply(f.Slope)
+	f.Intercept = OutputRounding.Apply(f.Intercept)
+	f.RSquared = OutputRounding.Apply(f.RSquared)
+	f.SlopeStdErr = OutputRounding.Apply(f.SlopeStdErr)
+	f.MeanLogScale = OutputRounding.Apply(f.MeanLogScale)
+	return f
+}
+
+// HasStdErr reports whether the fit had enough points (at least 3) to
+// estimate the residual variance and hence the slope's standard error
+func (f FitResult) HasStdErr() bool {
+	return f.Points > 2
+}
+
+// Predict returns the fitted LogMeasure at logScale
+func (f FitResult) Predict(logScale float64) float64 {
+	return f.Intercept + f.Slope*logScale
+}
+
+// Shifted returns the fit re-expressed for points moved by (-dx, -dy) in log
+// space: the slope is unchanged and the line still passes through the moved
+// points
+func (f FitResult) Shifted(dx, dy float64) FitResult {
+	f.Intercept += f.Slope*dx - dy
+	f.MeanLogScale -= dx
+	return f
+}
+
+// SlopeBand returns the LogMeasure predicted at logScale by the lines with
+// slope ±1 standard error pivoting about the centroid of the fitted points,
+// ordered low to high
+func (f FitResult) SlopeBand(logScale float64) (lo, hi float64) {
+	center := f.Predict(f.MeanLogScale)
+	dx := logScale - f.MeanLogScale
+	a := center + (f.Slope-f.SlopeStdErr)*dx
+	b := center + (f.Slope+f.SlopeStdErr)*dx
+	return math.Min(a, b), math.Max(a, b)
+}
+
+// FitLogLogSlope performs an ordinary least-squares fit of LogMeasure against
+// LogScale over the actual (non-projected) scales. Scales whose Scale or
+// Measure is non-positive are skipped since their logs are undefined.
+func FitLogLogSlope(scales []*Scale) (slope, intercept, rSquared float64, err error) {
+	fit, err := FitLogLog(scales)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return fit.Slope, fit.Intercept, fit.RSquared, nil
+}
+
+// FitLogLog is FitLogLogSlope returning the full fit, including the
+// standard error of the slope when there are at least 3 points
+func FitLogLog(scales []*Scale) (FitResult, error) {
+	return fitLogLog(scales, unitWeight, FitTrimHead, FitTrimTail)
+}
+
+// unitWeight gives every point the same weight
+func unitWeight(*Scale) float64 { return 1 }
+
+// FitLogLogSlopeWeighted is FitLogLogSlope with inverse-variance weights. The
+// MeasureError of each point is propagated through the log transform
+// (σ_log = σ / (Measure · ln base)); points without a positive MeasureError
+// get weight 1.
+func FitLogLogSlopeWeighted(scales []*Scale) (slope, intercept, rSquared float64, err error) {
+	fit, err := FitLogLogWeighted(scales)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return fit.Slope, fit.Intercept, fit.RSquared, nil
+}
+
+// FitLogLogWeighted is FitLogLogSlopeWeighted returning the full fit
+func FitLogLogWeighted(scales []*Scale) (FitResult, error) {
+	return fitLogLog(scales, measureWeight, FitTrimHead, FitTrimTail)
+}
+
+// measureWeight returns the inverse variance of a scale's LogMeasure, or 1
+// when it has no usable MeasureError
+func measureWeight(s *Scale) float64 {
+	if s.MeasureError == nil || *s.MeasureError <= 0 {
+		return 1
+	}
+	sigma := *s.MeasureError / (s.GetMeasure() * math.Log(s.GetLogBase()))
+	return 1 / (sigma * sigma)
+}
+
+// trimActuals returns the actual scales with valid logs in iteration order,
+// without the first head and last tail of them
+func trimActuals(scales []*Scale, head, tail int) ([]*Scale, error) {
+	var points []*Scale
+	for _, s := range scales {
+		if !s.IsProjected && s.LogScaleValid() && s.LogMeasureValid() {
+			points = append(points, s)
+		}
+	}
+	if head <= 0 && tail <= 0 {
+		return points, nil
+	}
+
+	head, tail = max(head, 0), max(tail, 0)
+	if len(points)-head-tail < 2 {
+		return nil, fmt.Errorf("%w: trimming %d head and %d tail points leaves %d of %d",
+			ErrInsufficientPoints, head, tail, max(len(points)-head-tail, 0), len(points))
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].EffectiveIteration() < points[j].EffectiveIteration() })
+	return points[head : len(points)-tail], nil
+}
+
+// fitLogLog performs the weighted least-squares fit shared by the fitting
+// functions over the actual scales with valid logs, trimmed by head and tail
+func fitLogLog(scales []*Scale, weight func(*Scale) float64, head, tail int) (FitResult, error) {
+	points, err := trimActuals(scales, head, tail)
+	if err != nil {
+		return FitResult{}, err
+	}
+
+	var xs, ys, ws []float64
+	for _, s := range points {
+		xs = append(xs, s.GetLogScale())
+		ys = append(ys, s.GetLogMeasure())
+		ws = append(ws, weight(s))
+	}
+
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return FitResult{}, ErrInsufficientPoints
+	}
+
+	var sumW, sumX, sumY float64
+	for i := range xs {
+		sumW += ws[i]
+		sumX += ws[i] * xs[i]
+		sumY += ws[i] * ys[i]
+	}
+	meanX := sumX / sumW
+	meanY := sumY / sumW
+
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx := xs[i] - meanX
+		dy := ys[i] - meanY
+		sxx += ws[i] * dx * dx
+		sxy += ws[i] * dx * dy
+		syy += ws[i] * dy * dy
+	}
+
+	if sxx == 0 {
+		return FitResult{}, ErrInsufficientPoints
+	}
+
+	fit := FitResult{Points: len(xs), MeanLogScale: meanX}
+	if head > 0 || tail > 0 {
+		fit.Trimmed = max(head, 0) + max(tail, 0)
+	}
+	fit.Slope = sxy / sxx
+	fit.Intercept = meanY - fit.Slope*meanX
+
+	// A perfectly flat response is fully explained by the fit
+	if syy == 0 {
+		fit.RSquared = 1
+	} else {
+		fit.RSquared = math.Min((sxy*sxy)/(sxx*syy), 1)
+	}
+
+	// SE(slope) = sqrt(SSE / (n-2) / Sxx); rounding can push SSE slightly negative
+	if fit.HasStdErr() {
+		sse := math.Max(syy-fit.Slope*sxy, 0)
+		fit.SlopeStdErr = math.Sqrt(sse / (n - 2) / sxx)
+	}
+
+	return fit, nil
+}
+
+// EstimateFractalDimension estimates the box-counting dimension of a fractal
+// system as the negative of the fitted log-log slope across actual iterations.
+// The system is the one the scales are bound to (see BuildSystemsMap), and
+// must declare a FractalDimension.
+func EstimateFractalDimension(scales []*Scale) (float64, error) {
+	var system *System
+	for _, s := range scales {
+		if s.system != nil {
+			system = s.system
+			break
+		}
+	}
+	if system == nil {
+		return 0, fmt.Errorf("no scale is bound to a system (see BindSystem)")
+	}
+	if system.FractalDimension == nil {
+		return 0, fmt.Errorf("system %s has no declared FractalDimension", system.SystemID)
+	}
+
+	slope, _, _, err := FitLogLogSlope(scales)
+	if err != nil {
+		return 0, err
+	}
+	return -slope, nil
+}
+
+// DimensionConvergenceTolerance is how close the last two local dimension
+// estimates must be for DimensionConverged to report convergence
+var DimensionConvergenceTolerance = 0.01
+
+// DimensionConvergence returns the local dimension estimate between each
+// pair of consecutive actual points in iteration order, the negative of the
+// slope ΔLogMeasure/ΔLogScale between them. For a finite fractal the
+// sequence should settle on the dimension; a steady drift means the global
+// fit is averaging over a range that is not yet scaling. Untrimmed; points
+// at the same LogScale are skipped.
+func DimensionConvergence(scales []*Scale) ([]float64, error) {
+	points, err := trimActuals(scales, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) < 2 {
+		return nil, ErrInsufficientPoints
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].EffectiveIteration() < points[j].EffectiveIteration() })
+
+	var local []float64
+	for k := 0; k+1 < len(points); k++ {
+		dx := points[k+1].GetLogScale() - points[k].GetLogScale()
+		if dx == 0 {
+			continue
+		}
+		local = append(local, -(points[k+1].GetLogMeasure()-points[k].GetLogMeasure())/dx)
+	}
+	if len(local) == 0 {
+		return nil, ErrInsufficientPoints
+	}
+	return local, nil
+}
+
+// DimensionConverged reports whether the last two local estimates from
+// DimensionConvergence differ by at most tol. A single estimate cannot show
+// convergence and reports false.
+func DimensionConverged(local []float64, tol float64) bool {
+	n := len(local)
+	return n >= 2 && math.Abs(local[n-1]-local[n-2]) <= tol
+}
+
+// RichardsonDimension extrapolates the local dimension estimates to their
+// limit with Aitken's Δ² process on the last three, the Richardson step for
+// a sequence converging geometrically. ok is false with fewer than three
+// estimates; an already constant tail returns its last value.
+func RichardsonDimension(local []float64) (limit float64, ok bool) {
+	n := len(local)
+	if n < 3 {
+		return 0, false
+	}
+	a, b, c := local[n-3], local[n-2], local[n-1]
+	denom := (c - b) - (b - a)
+	if math.Abs(denom) < 1e-12 {
+		return c, true
+	}
+	return c - (c-b)*(c-b)/denom, true
+}
+
+// Crossover detection thresholds: each segment needs enough points to have a
+// residual, the split must at least halve the single-line residual, and the
+// slopes must differ by more than CrossoverMinSlopeChange
+var (
+	CrossoverMinSegment     = 3
+	CrossoverMinSlopeChange = 0.05
+)
+
+// DetectCrossover looks for a change between two power-law regimes by fitting
+// separate lines to the actual points before and after every possible
+// breakpoint (in iteration order) and keeping the split with the smallest
+// total squared residual. iteration is the first (effective, so possibly
+// fractional) iteration of the second regime. found is false when no split
+// improves on a single line enough to count as a crossover (see the Crossover
+// thresholds). It ignores FitTrimHead and FitTrimTail, since a second regime
+// usually shows at the ends.
+func DetectCrossover(scales []*Scale) (iteration, slope1, slope2 float64, found bool) {
+	var points []*Scale
+	for _, s := range scales {
+		if !s.IsProjected && s.LogScaleValid() && s.LogMeasureValid() {
+			points = append(points, s)
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].EffectiveIteration() < points[j].EffectiveIteration() })
+	if len(points) < 2*CrossoverMinSegment {
+		return 0, 0, 0, false
+	}
+
+	single, err := fitLogLog(points, unitWeight, 0, 0)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	singleSSE := sumSquaredResiduals(points, single)
+
+	bestSSE := math.Inf(1)
+	for k := CrossoverMinSegment; k <= len(points)-CrossoverMinSegment; k++ {
+		left, errL := fitLogLog(points[:k], unitWeight, 0, 0)
+		right, errR := fitLogLog(points[k:], unitWeight, 0, 0)
+		if errL != nil || errR != nil {
+			continue
+		}
+		sse := sumSquaredResiduals(points[:k], left) + sumSquaredResiduals(points[k:], right)
+		if sse < bestSSE {
+			bestSSE = sse
+			iteration, slope1, slope2 = points[k].EffectiveIteration(), left.Slope, right.Slope
+		}
+	}
+
+	found = bestSSE <= singleSSE/2 && math.Abs(slope2-slope1) > CrossoverMinSlopeChange
+	return iteration, slope1, slope2, found
+}
+
+// sumSquaredResiduals returns the squared LogMeasure residuals of the points from a fit
+func sumSquaredResiduals(points []*Scale, fit FitResult) float64 {
+	var sse float64
+	for _, s := range points {
+		r := s.GetLogMeasure() - fit.Predict(s.GetLogScale())
+		sse += r * r
+	}
+	return sse
+}
+
+// BootstrapConfidence is the two-sided confidence level of BootstrapDimensionCI
+const BootstrapConfidence = 0.95
+
+// BootstrapDimensionCI estimates a percentile confidence interval for the
+// fractal dimension (the negated log-log slope) by resampling the actual
+// points with replacement iterations times and refitting each resample. The
+// interval covers BootstrapConfidence of the refitted dimensions; median is
+// their 50th percentile. The points are trimmed by FitTrimHead and
+// FitTrimTail before resampling. Resamples whose points all share one Scale
+// cannot be fitted and are skipped. The same seed always gives the same interval.
+func BootstrapDimensionCI(scales []*Scale, iterations int, seed int64) (lo, hi, median float64, err error) {
+	if iterations < 1 {
+		return 0, 0, 0, fmt.Errorf("bootstrap needs at least 1 iteration, got %d", iterations)
+	}
+
+	points, err := trimActuals(scales, FitTrimHead, FitTrimTail)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if _, err := fitLogLog(points, unitWeight, 0, 0); err != nil {
+		return 0, 0, 0, err
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	sample := make([]*Scale, len(points))
+	dims := make([]float64, 0, iterations)
+	for i := 0; i < iterations; i++ {
+		for j := range sample {
+			sample[j] = points[rng.Intn(len(points))]
+		}
+		fit, err := fitLogLog(sample, unitWeight, 0, 0)
+		if err != nil {
+			continue
+		}
+		dims = append(dims, -fit.Slope)
+	}
+	if len(dims) == 0 {
+		return 0, 0, 0, ErrInsufficientPoints
+	}
+
+	sort.Float64s(dims)
+	alpha := (1 - BootstrapConfidence) / 2
+	return percentile(dims, alpha), percentile(dims, 1-alpha), percentile(dims, 0.5), nil
+}
+
+// percentile returns the p-th quantile (0 <= p <= 1) of sorted values,
+// interpolating linearly between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// ProjectionAnchor selects where ProjectScales pins the theoretical line.
+// Projections always follow the theoretical slope; the anchor only sets the
+// line's intercept, shifting every projected LogMeasure by the same amount.
+type ProjectionAnchor string
+
+const (
+	// AnchorFirst passes the line through the actual scale with the lowest
+	// iteration (the default). Noise in that one point shifts all projections.
+	AnchorFirst ProjectionAnchor = "first"
+	// AnchorLast passes the line through the actual scale with the highest
+	// iteration, the nearest point to projections beyond the data.
+	AnchorLast ProjectionAnchor = "last"
+	// AnchorFit uses the least-squares intercept of the theoretical-slope line
+	// through all actual scales, averaging out noise in individual points.
+	AnchorFit ProjectionAnchor = "fit"
+)
+
+// ParseProjectionAnchor parses "first", "last" or "fit"
+func ParseProjectionAnchor(s string) (ProjectionAnchor, error) {
+	switch anchor := ProjectionAnchor(s); anchor {
+	case AnchorFirst, AnchorLast, AnchorFit:
+		return anchor, nil
+	}
+	return "", fmt.Errorf("unknown projection anchor %q (expected %q, %q or %q)", s, AnchorFirst, AnchorLast, AnchorFit)
+}
+
+// ProjectScales generates projected scales for the given iterations by
+// extending the theoretical log-log line through the anchor (an empty anchor
+// means AnchorFirst). The actual scales must already be computed. Returns nil
+// when there are no actuals.
+func ProjectScales(system *System, actuals []*Scale, iterations []int, anchor ProjectionAnchor) []*Scale {
+	intercept, ok := projectionIntercept(actuals, system.TheoreticalLogLogSlope, anchor)
+	if !ok {
+		return nil
+	}
+
+	systems := SystemsMap{system.SystemID: system}
+	base := system.EffectiveLogBase()
+
+	projected := make([]*Scale, 0, len(iterations))
+	for _, iter := range iterations {
+		scaleValue := system.BaseScale * system.ScaleFactorPower(float64(iter))
+		logMeasure := intercept + system.TheoreticalLogLogSlope*logBase(scaleValue, base)
+
+		scale := &Scale{
+			ScaleID:     fmt.Sprintf("%s_%d", system.SystemID, iter),
+			System:      system.SystemID,
+			Iteration:   iter,
+			Measure:     floatPtr(math.Pow(base, logMeasure)),
+			IsProjected: true,
+		}
+		if err := scale.CalculateAllFields(systems); err != nil {
+			continue
+		}
+		projected = append(projected, scale)
+	}
+	return projected
+}
+
+// PredictScaleForMeasure inverts a projection: it returns the Scale at which
+// the line with the system's theoretical slope through anchor reaches
+// targetMeasure, and the (possibly fractional) iteration at which the system
+// reaches that Scale. The anchor must already be computed. A zero slope has
+// no unique solution and is an error. When only the iteration cannot be
+// solved (see SolveIterationForScale), scale is still returned with the error.
+func PredictScaleForMeasure(system *System, anchor *Scale, targetMeasure float64) (scale, iteration float64, err error) {
+	slope := system.TheoreticalLogLogSlope
+	if slope == 0 {
+		return 0, 0, fmt.Errorf("system %s: theoretical slope is 0, so no unique Scale gives Measure %g", system.SystemID, targetMeasure)
+	}
+	if !(targetMeasure > 0) || math.IsInf(targetMeasure, 0) {
+		return 0, 0, fmt.Errorf("system %s: target Measure must be a positive number, got %g", system.SystemID, targetMeasure)
+	}
+	if anchor == nil || !anchor.LogScaleValid() || !anchor.LogMeasureValid() {
+		return 0, 0, fmt.Errorf("system %s: anchor scale has no valid LogScale and LogMeasure", system.SystemID)
+	}
+
+	base := anchor.GetLogBase()
+	logScale := anchor.GetLogScale() + (logBase(targetMeasure, base)-anchor.GetLogMeasure())/slope
+	scale = math.Pow(base, logScale)
+
+	iteration, err = (&Scale{}).SolveIterationForScale(system, scale)
+	if err != nil {
+		return scale, 0, err
+	}
+	return scale, iteration, nil
+}
+
+// projectionIntercept returns the intercept of the line with the given slope
+// through the anchor, or false when there is no actual scale to anchor on
+func projectionIntercept(actuals []*Scale, slope float64, anchor ProjectionAnchor) (float64, bool) {
+	var point *Scale
+	switch anchor {
+	case AnchorFit:
+		return theoreticalIntercept(actuals, slope)
+	case AnchorLast:
+		point = extremeActual(actuals, func(a, b float64) bool { return a > b })
+	default:
+		point = extremeActual(actuals, func(a, b float64) bool { return a < b })
+	}
+	if point == nil {
+		return 0, false
+	}
+	return point.GetLogMeasure() - slope*point.GetLogScale(), true
+}
+
+// extremeActual returns the actual scale with valid logs whose iteration is
+// preferred by better (lowest for <, highest for >), wherever the iterations
+// fall; gaps and missing Measures are skipped rather than assumed away
+func extremeActual(scales []*Scale, better func(a, b float64) bool) *Scale {
+	var best *Scale
+	for _, s := range scales {
+		if s.IsProjected || s.IsInterpolated || !s.LogScaleValid() || !s.LogMeasureValid() {
+			continue
+		}
+		if best == nil || better(s.EffectiveIteration(), best.EffectiveIteration()) {
+			best = s
+		}
+	}
+	return best
+}
+
+// theoreticalIntercept returns the intercept of the line with the system's
+// theoretical slope that best fits the actual points (least squares with the
+// slope held fixed), i.e. the mean of LogMeasure - slope*LogScale
+func theoreticalIntercept(scales []*Scale, slope float64) (float64, bool) {
+	var sum float64
+	n := 0
+	for _, s := range scales {
+		if s.IsProjected || !s.LogScaleValid() || !s.LogMeasureValid() {
+			continue
+		}
+		sum += s.GetLogMeasure() - slope*s.GetLogScale()
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// Residual is the signed distance in LogMeasure of one scale from a predicted line
+type Residual struct {
+	Scale *Scale
+	Value float64
+}
+
+// TheoreticalResiduals returns the residual of each actual scale from the line
+// with the system's theoretical slope through the data. A zero slope compares
+// against a horizontal line at the mean.
+func TheoreticalResiduals(scales []*Scale, system *System) []Residual {
+	slope := system.TheoreticalLogLogSlope
+	intercept, ok := theoreticalIntercept(scales, slope)
+	if !ok {
+		return nil
+	}
+
+	var residuals []Residual
+	for _, s := range scales {
+		if s.IsProjected || !s.LogScaleValid() || !s.LogMeasureValid() {
+			continue
+		}
+		residuals = append(residuals, Residual{
+			Scale: s,
+			Value: s.GetLogMeasure() - (intercept + slope*s.GetLogScale()),
+		})
+	}
+	return residuals
+}
+
+// FindSlopeOutliers returns the ScaleIDs of actual scales whose LogMeasure
+// deviates from the theoretical slope line through the data by more than threshold
+func FindSlopeOutliers(scales []*Scale, system *System, threshold float64) []string {
+	var outliers []string
+	for _, r := range TheoreticalResiduals(scales, system) {
+		if math.Abs(r.Value) > threshold {
+			outliers = append(outliers, r.Scale.ScaleID)
+		}
+	}
+	return outliers
+}
+
+// ChiSquaredVsTheoretical tests whether the actual scales are statistically
+// consistent with the system's theoretical slope. Residuals are taken from
+// the theoretical-slope line through the data (its intercept is the one
+// fitted parameter, so dof = n-1) and weighted by the inverse variance of
+// each point's MeasureError; points without one get unit variance in log
+// units, which makes the test only meaningful when errors are supplied. The
+// p-value is the probability of a chi-squared at least this large if the
+// power law holds.
+func ChiSquaredVsTheoretical(scales []*Scale, system *System) (chi2, pValue float64, err error) {
+	slope := system.TheoreticalLogLogSlope
+
+	var points []*Scale
+	var sumW, sumWI float64
+	for _, s := range scales {
+		if s.IsProjected || !s.LogScaleValid() || !s.LogMeasureValid() {
+			continue
+		}
+		w := measureWeight(s)
+		points = append(points, s)
+		sumW += w
+		sumWI += w * (s.GetLogMeasure() - slope*s.GetLogScale())
+	}
+	if len(points) < 2 {
+		return 0, 0, ErrInsufficientPoints
+	}
+
+	intercept := sumWI / sumW
+	for _, s := range points {
+		r := s.GetLogMeasure() - (intercept + slope*s.GetLogScale())
+		chi2 += measureWeight(s) * r * r
+	}
+
+	dof := float64(len(points) - 1)
+	return chi2, upperIncompleteGamma(dof/2, chi2/2), nil
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma
+// function Q(a, x), the chi-squared survival function at 2x with 2a degrees
+// of freedom. It uses the series for x < a+1 and the continued fraction
+// otherwise (Numerical Recipes §6.2).
+func upperIncompleteGamma(a, x float64) float64 {
+	const (
+		maxIter = 200
+		eps     = 1e-15
+		tiny    = 1e-300
+	)
+	if x <= 0 {
+		return 1
+	}
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return math.Max(1-sum*prefix, 0)
+	}
+
+	// Modified Lentz evaluation of the continued fraction
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIter; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return prefix * h
+}
+
+// SystemStats summarizes how closely a system's actual points follow its
+// theoretical slope line, in log units of Measure
+type SystemStats struct {
+	SystemID        string
+	Points          int
+	MinAbsResidual  float64
+	MaxAbsResidual  float64
+	MeanAbsResidual float64
+	RMSResidual     float64
+	// ScaleDecades is how many powers of ten the actual points span in Scale
+	ScaleDecades float64
+}
+
+// MinScaleDecades is the Scale span, in decades, below which a system's
+// fitted slope is considered unreliable
+var MinScaleDecades = 1.0
+
+// NarrowRange reports whether the actual points span fewer than
+// MinScaleDecades decades of Scale, too few to trust a fitted slope
+func (s SystemStats) NarrowRange() bool {
+	return s.ScaleDecades < MinScaleDecades
+}
+
+// ComputeSystemStats computes residual statistics of the actual scales against
+// the theoretical slope line through the data
+func ComputeSystemStats(scales []*Scale, system *System) (SystemStats, error) {
+	stats := SystemStats{SystemID: system.SystemID}
+
+	residuals := TheoreticalResiduals(scales, system)
+	if len(residuals) == 0 {
+		return stats, ErrInsufficientPoints
+	}
+
+	var sumAbs, sumSq float64
+	stats.MinAbsResidual = math.Inf(1)
+	minLog, maxLog := math.Inf(1), math.Inf(-1)
+	for _, r := range residuals {
+		minLog = math.Min(minLog, r.Scale.GetLogScale())
+		maxLog = math.Max(maxLog, r.Scale.GetLogScale())
+		abs := math.Abs(r.Value)
+		stats.MinAbsResidual = math.Min(stats.MinAbsResidual, abs)
+		stats.MaxAbsResidual = math.Max(stats.MaxAbsResidual, abs)
+		sumAbs += abs
+		sumSq += r.Value * r.Value
+	}
+
+	n := float64(len(residuals))
+	stats.Points = len(residuals)
+	stats.MeanAbsResidual = sumAbs / n
+	stats.RMSResidual = math.Sqrt(sumSq / n)
+	stats.ScaleDecades = (maxLog - minLog) * math.Log10(residuals[0].Scale.GetLogBase())
+	return stats, nil
+}
+
+// ValidateSystemSlopes checks that each system's fitted slope over its actual
+// scales is within tol of its TheoreticalLogLogSlope. Results are keyed by
+// system ID (in the ScaleID field) and sorted by it; systems that cannot be
+// fitted fail with the fit error.
+func ValidateSystemSlopes(scalesBySystem map[string][]*Scale, systems SystemsMap, tol float64) []ValidationResult {
+	systemIDs := make([]string, 0, len(scalesBySystem))
+	for id := range scalesBySystem {
+		systemIDs = append(systemIDs, id)
+	}
+	sort.Strings(systemIDs)
+
+	results := make([]ValidationResult, 0, len(systemIDs))
+	for _, id := range systemIDs {
+		result := ValidationResult{ScaleID: id, Passed: true, Mismatches: []string{}}
+
+		system, ok := systems[id]
+		if !ok {
+			result.Passed = false
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("%v: %s", ErrUnknownSystem, id))
+			results = append(results, result)
+			continue
+		}
+
+		slope, _, _, err := FitLogLogSlope(scalesBySystem[id])
+		if err != nil {
+			result.Passed = false
+			result.Mismatches = append(result.Mismatches, err.Error())
+		} else if math.Abs(slope-system.TheoreticalLogLogSlope) > tol {
+			result.Passed = false
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("slope: expected %.6f, fitted %.6f (tolerance %g)", system.TheoreticalLogLogSlope, slope, tol))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// ValidateMonotonicScale checks that each system's Scale moves steadily with
+// iteration: strictly decreasing when ScaleFactor < 1 and strictly increasing
+// when it is > 1. A ScaleFactor of exactly 1 keeps Scale constant and is
+// allowed. The scales must already be computed; they are compared per system
+// in order of their integer Iteration, so a scale whose IterationFloat or
+// data disagrees with its Iteration shows up out of order. The ScaleID of each
+// scale that breaks the order relative to the one before it is returned.
+func ValidateMonotonicScale(scales []*Scale) []string {
+	bySystem := make(map[string][]*Scale)
+	var systemIDs []string
+	for _, s := range scales {
+		if s.scale == nil {
+			continue
+		}
+		if _, seen := bySystem[s.System]; !seen {
+			systemIDs = append(systemIDs, s.System)
+		}
+		bySystem[s.System] = append(bySystem[s.System], s)
+	}
+
+	var broken []string
+	for _, id := range systemIDs {
+		group := bySystem[id]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Iteration < group[j].Iteration })
+		for i := 1; i < len(group); i++ {
+			prev, cur := group[i-1], group[i]
+			if cur.Iteration == prev.Iteration {
+				continue
+			}
+			factor := cur.GetScaleFactor()
+			switch {
+			case factor < 1 && cur.GetScale() >= prev.GetScale(),
+				factor > 1 && cur.GetScale() <= prev.GetScale():
+				broken = append(broken, cur.ScaleID)
+			}
+		}
+	}
+	return broken
+}
+
+// System classes recognized by ValidateSystemClass
+const (
+	ClassFractal  = "fractal"
+	ClassPowerLaw = "power_law"
+)
+
+// DefaultSlopeDimensionTolerance allows for FractalDimension and
+// TheoreticalLogLogSlope being quoted to three decimals, while still catching
+// a sign error or a slope taken from the wrong convention
+const DefaultSlopeDimensionTolerance = 0.001
+
+// SlopeDimensionTolerance is how far a system's TheoreticalLogLogSlope may
+// be from -FractalDimension before loading it records a warning
+var SlopeDimensionTolerance = DefaultSlopeDimensionTolerance
+
+// CheckSlopeDimensionConsistency checks that the system's theoretical slope
+// equals -FractalDimension (the box-counting and mass-radius convention)
+// within tol. Systems without a FractalDimension always pass.
+func CheckSlopeDimensionConsistency(system *System, tol float64) error {
+	if system.FractalDimension == nil {
+		return nil
+	}
+	slope := system.TheoreticalLogLogSlope
+	if math.Abs(slope+*system.FractalDimension) > tol {
+		return fmt.Errorf("slope %g does not equal -FractalDimension (%g) under the box-counting convention",
+			slope, -*system.FractalDimension)
+	}
+	return nil
+}
+
+// ValidateSystemClass checks a system's metadata for signs of
+// misclassification: a fractal needs a FractalDimension and a negative
+// slope, and a power law should not declare a FractalDimension. Whether the
+// slope matches -FractalDimension is checked at load for every class (see
+// CheckSlopeDimensionConsistency). All problems found are returned together
+// as a *DataValidationError.
+func ValidateSystemClass(system *System) error {
+	var problems []string
+	slope := system.TheoreticalLogLogSlope
+
+	switch system.Class {
+	case ClassFractal:
+		if system.FractalDimension == nil {
+			problems = append(problems, "fractal system has no FractalDimension")
+		}
+		if slope >= 0 {
+			problems = append(problems, fmt.Sprintf("fractal system has non-negative slope %g", slope))
+		}
+	case ClassPowerLaw:
+		if system.FractalDimension != nil {
+			problems = append(problems, fmt.Sprintf("power-law system declares FractalDimension %g", *system.FractalDimension))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown class %q (expected %q or %q)", system.Class, ClassFractal, ClassPowerLaw))
+	}
+
+	if len(problems) > 0 {
+		return &DataValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// InterpolateMissingScales fills integer iterations that no given scale
+// covers between actual scales by interpolating LogMeasure linearly in
+// LogScale between the bracketing actual points. The actual iterations need
+// not be contiguous or start at 0 (e.g. 2, 5, 11), and may be fractional via
+// IterationFloat. Iterations before the first or after the last actual are
+// left to projection. The actuals must already be computed.
+func InterpolateMissingScales(system *System, actuals []*Scale) []*Scale {
+	var points []*Scale
+	present := make(map[float64]bool)
+	for _, s := range actuals {
+		present[s.EffectiveIteration()] = true
+		if s.IsProjected || s.IsInterpolated || !s.LogScaleValid() || !s.LogMeasureValid() {
+			continue
+		}
+		points = append(points, s)
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].EffectiveIteration() < points[j].EffectiveIteration() })
+
+	systems := SystemsMap{system.SystemID: system}
+	base := system.EffectiveLogBase()
+
+	var interpolated []*Scale
+	for k := 0; k+1 < len(points); k++ {
+		lo, hi := points[k], points[k+1]
+		span := hi.GetLogScale() - lo.GetLogScale()
+		first := int(math.Floor(lo.EffectiveIteration())) + 1
+		for iter := first; float64(iter) < hi.EffectiveIteration(); iter++ {
+			if present[float64(iter)] || span == 0 {
+				continue
+			}
+			scaleValue := system.BaseScale * system.ScaleFactorPower(float64(iter))
+			t := (logBase(scaleValue, base) - lo.GetLogScale()) / span
+			logMeasure := lo.GetLogMeasure() + t*(hi.GetLogMeasure()-lo.GetLogMeasure())
+
+			scale := &Scale{
+				ScaleID:        fmt.Sprintf("%s_%d", system.SystemID, iter),
+				System:         system.SystemID,
+				Iteration:      iter,
+				Measure:        floatPtr(math.Pow(base, logMeasure)),
+				IsInterpolated: true,
+			}
+			if err := scale.CalculateAllFields(systems); err != nil {
+				continue
+			}
+			interpolated = append(interpolated, scale)
+		}
+	}
+	return interpolated
+}
@@ -0,0 +1,124 @@
+package rulebook
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// noisySierpinski returns computed Sierpinski scales for iterations 0-7 with
+// small Gaussian noise on LogMeasure
+func noisySierpinski(t *testing.T) []*Scale {
+	t.Helper()
+	systems := []System{{
+		SystemID:               "Sierpinski",
+		ScaleFactor:            0.5,
+		BaseScale:              1,
+		TheoreticalLogLogSlope: -1.5849625,
+		FractalDimension:       floatPtr(1.5849625),
+	}}
+	generated := GenerateSyntheticScales(&systems[0], 8, 0.02, 3)
+	m := BuildSystemsMap(systems, generated)
+	scales := make([]*Scale, len(generated))
+	for i := range generated {
+		if err := generated[i].CalculateAllFields(m); err != nil {
+			t.Fatal(err)
+		}
+		scales[i] = &generated[i]
+	}
+	return scales
+}
+
+func TestEstimateFractalDimension(t *testing.T) {
+	scales := noisySierpinski(t)
+	estimated, err := EstimateFractalDimension(scales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(estimated-1.5849625) > 0.05 {
+		t.Errorf("estimated dimension %g, want about 1.585", estimated)
+	}
+
+	unbound := *scales[0]
+	unbound.system = nil
+	if _, err := EstimateFractalDimension([]*Scale{&unbound}); err == nil {
+		t.Error("expected an error for scales not bound to a system")
+	}
+}
+
+func TestBootstrapDimensionCIDeterministic(t *testing.T) {
+	scales := noisySierpinski(t)
+
+	lo, hi, median, err := BootstrapDimensionCI(scales, 500, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(lo <= median && median <= hi) {
+		t.Errorf("interval [%g, %g] does not contain its median %g", lo, hi, median)
+	}
+	if lo > 1.5849625 || hi < 1.5849625 {
+		t.Errorf("interval [%g, %g] misses the theoretical dimension 1.585", lo, hi)
+	}
+
+	lo2, hi2, median2, err := BootstrapDimensionCI(scales, 500, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lo2 != lo || hi2 != hi || median2 != median {
+		t.Errorf("seed 42 gave [%g, %g] median %g, then [%g, %g] median %g", lo, hi, median, lo2, hi2, median2)
+	}
+
+	lo3, hi3, _, err := BootstrapDimensionCI(scales, 500, 43)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lo3 == lo && hi3 == hi {
+		t.Errorf("seeds 42 and 43 gave the same interval [%g, %g]", lo, hi)
+	}
+
+	if _, _, _, err := BootstrapDimensionCI(scales, 0, 42); err == nil {
+		t.Error("expected an error for 0 iterations")
+	}
+}
+
+func TestDetectCrossoverFractionalIterations(t *testing.T) {
+	systems := []System{{SystemID: "Twin", ScaleFactor: 0.5, BaseScale: 1, TheoreticalLogLogSlope: -1}}
+
+	// Slope -1 up to iteration 3, then slope -2 from 3.5, sampled every half
+	// step so the second regime starts at a fractional iteration
+	var generated []Scale
+	for i := 0; i < 12; i++ {
+		iter := float64(i) / 2
+		logScale := iter * math.Log10(0.5)
+		logMeasure := -logScale
+		if iter >= 3.5 {
+			logMeasure = -2 * logScale
+		}
+		generated = append(generated, Scale{
+			ScaleID:        fmt.Sprintf("Twin_%d", i),
+			System:         "Twin",
+			Iteration:      int(iter),
+			IterationFloat: floatPtr(iter),
+			Measure:        floatPtr(math.Pow(10, logMeasure)),
+		})
+	}
+	m := BuildSystemsMap(systems, generated)
+	scales := make([]*Scale, len(generated))
+	for i := range generated {
+		if err := generated[i].CalculateAllFields(m); err != nil {
+			t.Fatal(err)
+		}
+		scales[i] = &generated[i]
+	}
+
+	iteration, slope1, slope2, found := DetectCrossover(scales)
+	if !found {
+		t.Fatal("crossover not found")
+	}
+	if iteration != 3.5 {
+		t.Errorf("crossover at iteration %g, want 3.5", iteration)
+	}
+	if math.Abs(slope1+1) > 1e-3 || math.Abs(slope2+2) > 1e-3 {
+		t.Errorf("slopes %g → %g, want -1 → -2", slope1, slope2)
+	}
+}
@@ -0,0 +1,91 @@
+//
+// Batch Computation
+//
+// Computes derived values for many scales at once, optionally in parallel
+//
+
+package rulebook
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ProgressFunc receives the number of scales computed so far and the total
+type ProgressFunc func(done, total int)
+
+// DefaultProgressEvery is how many scales pass between progress reports
+const DefaultProgressEvery = 1000
+
+// ComputeScalesParallel runs CalculateAllFields on every scale using a pool of
+// workers goroutines (runtime.NumCPU() when workers <= 0). Each scale is handled
+// by exactly one goroutine, so its cached fields are never shared; the SystemsMap
+// is only read. The returned slice is aligned with scales and holds nil for
+// every scale that computed successfully.
+func ComputeScalesParallel(scales []Scale, systems SystemsMap, workers int) []error {
+	return ComputeScalesWithProgress(scales, systems, workers, nil, 0)
+}
+
+// ComputeScalesWithProgress is ComputeScalesParallel reporting progress after
+// every `every` scales (DefaultProgressEvery when <= 0) and once at the end.
+// Calls to progress are serialized but may come from any worker goroutine.
+func ComputeScalesWithProgress(scales []Scale, systems SystemsMap, workers int, progress ProgressFunc, every int) []error {
+	errs, _ := computeScalesTimed(scales, systems, workers, progress, every)
+	return errs
+}
+
+// computeScalesTimed is ComputeScalesWithProgress also returning the time
+// spent computing each scale, measured on the monotonic clock
+func computeScalesTimed(scales []Scale, systems SystemsMap, workers int, progress ProgressFunc, every int) ([]error, []time.Duration) {
+	if every <= 0 {
+		every = DefaultProgressEvery
+	}
+
+	var mu sync.Mutex
+	done := 0
+	report := func() {
+		if progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if done%every == 0 || done == len(scales) {
+			progress(done, len(scales))
+		}
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(scales) {
+		workers = len(scales)
+	}
+
+	errs := make([]error, len(scales))
+	durations := make([]time.Duration, len(scales))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				start := time.Now()
+				errs[i] = scales[i].CalculateAllFields(systems)
+				durations[i] = time.Since(start)
+				report()
+			}
+		}()
+	}
+
+	for i := range scales {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return errs, durations
+}
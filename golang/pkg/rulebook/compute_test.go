@@ -0,0 +1,50 @@
+package rulebook
+
+import (
+	"reflect"
+	"testing"
+)
+
+// syntheticStressScales generates a few thousand noisy scales across the
+// systems of the repo's base data
+func syntheticStressScales(t *testing.T) ([]Scale, SystemsMap) {
+	t.Helper()
+	baseData, err := LoadBaseData(testDataPath("base-data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	systems := BuildSystemsMap(baseData.Systems)
+
+	var scales []Scale
+	for seed := int64(1); seed <= 20; seed++ {
+		for i := range baseData.Systems {
+			scales = append(scales, GenerateSyntheticScales(&baseData.Systems[i], 30, 0.05, seed)...)
+		}
+	}
+	return scales, systems
+}
+
+// Run with -race: each scale's cached fields must only be touched by the
+// goroutine computing it
+func TestComputeScalesParallelMatchesSerial(t *testing.T) {
+	serial, systems := syntheticStressScales(t)
+	parallel, _ := syntheticStressScales(t)
+	if len(serial) < 2000 {
+		t.Fatalf("only %d synthetic scales", len(serial))
+	}
+
+	serialErrs := make([]error, len(serial))
+	for i := range serial {
+		serialErrs[i] = serial[i].CalculateAllFields(systems)
+	}
+	parallelErrs := ComputeScalesParallel(parallel, systems, 8)
+
+	for i := range serial {
+		if (serialErrs[i] == nil) != (parallelErrs[i] == nil) {
+			t.Fatalf("scale %d: serial error %v, parallel error %v", i, serialErrs[i], parallelErrs[i])
+		}
+		if want, got := serial[i].ToOutputMap(), parallel[i].ToOutputMap(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("scale %d: parallel output\n%v\ndiffers from serial\n%v", i, got, want)
+		}
+	}
+}
@@ -7,8 +7,27 @@
 package rulebook
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
 )
 
 // BaseData represents the structure of base-data.json
@@ -18,6 +37,9 @@ type BaseData struct {
 	Source      string    `json:"source"`
 	Systems     []System  `json:"systems"`
 	Scales      []Scale   `json:"scales"`
+
+	// SHA256 is the hash of the file's content, set by LoadBaseData
+	SHA256 string `json:"-"`
 }
 
 // TestInput represents the structure of test-input.json
@@ -26,6 +48,16 @@ type TestInput struct {
 	Generated   string  `json:"generated"`
 	Source      string  `json:"source"`
 	Scales      []Scale `json:"scales"`
+
+	// Systems optionally overrides base-data systems for "what-if" runs:
+	// each replaces the base system with the same SystemID (or adds a new
+	// one) for every scale computed, base and test alike. Across several
+	// test inputs, later files take precedence.
+	Systems []System `json:"systems,omitempty"`
+
+	// Files lists each file loaded into this input with the hash of its
+	// content, set by LoadTestInput and LoadTestInputs
+	Files []FileDigest `json:"-"`
 }
 
 // AnswerKey represents the structure of answer-key.json
@@ -34,6 +66,9 @@ type AnswerKey struct {
 	Generated   string                   `json:"generated"`
 	Source      string                   `json:"source"`
 	Scales      []map[string]interface{} `json:"scales"`
+
+	// SHA256 is the hash of the file's content, set by LoadAnswerKey
+	SHA256 string `json:"-"`
 }
 
 // TestResults represents the output format
@@ -41,59 +76,412 @@ type TestResults struct {
 	Platform  string                   `json:"platform"`
 	Timestamp string                   `json:"timestamp"`
 	Scales    []map[string]interface{} `json:"scales"`
+
+	// Fits holds the log-log fit of each system's actual scales, by system ID
+	Fits map[string]FitResult `json:"fits,omitempty"`
+
+	// ComputeMillis is the time spent computing each system's scales, by
+	// system ID, when timing is recorded
+	ComputeMillis map[string]float64 `json:"computeMillis,omitempty"`
+
+	// Provenance records the input files the results were computed from
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance records which inputs produced a set of results, so a saved
+// results file can be traced back to specific data files and tool version
+type Provenance struct {
+	BaseData    FileDigest   `json:"baseData"`
+	TestInputs  []FileDigest `json:"testInputs"`
+	AnswerKey   FileDigest   `json:"answerKey"`
+	ToolVersion string       `json:"toolVersion"`
+}
+
+// FileDigest is a data file's path or URL and the SHA-256 of its content.
+// Gzipped files are hashed after decompression, so a file hashes the same
+// whether or not it was compressed.
+type FileDigest struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
 }
 
-// LoadBaseData loads base-data.json
+// contentDigest returns the hex SHA-256 of data
+func contentDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// gzipMagic is the two-byte header that starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// URLTimeout bounds each HTTP request made to load data from a URL
+var URLTimeout = 30 * time.Second
+
+// IsURL reports whether a data path is an http:// or https:// URL
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// dataFileName returns the part of a path or URL that names the file, for
+// detecting its format by extension; a URL's query and fragment are dropped
+func dataFileName(path string) string {
+	if !IsURL(path) {
+		return path
+	}
+	if u, err := url.Parse(path); err == nil {
+		return u.Path
+	}
+	return path
+}
+
+// openData opens a local file, or fetches a URL with URLTimeout. A response
+// other than 200 OK is an error giving the status code.
+func openData(pathOrURL string) (io.ReadCloser, error) {
+	if !IsURL(pathOrURL) {
+		return os.Open(pathOrURL)
+	}
+	
+	client := &http.Client{Timeout: URLTimeout}
+	resp, err := client.Get(pathOrURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: HTTP status %d (%s)", pathOrURL, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return resp.Body, nil
+}
+
+// readData reads a data file or URL, transparently decompressing it when it
+// has a .gz extension or starts with the gzip magic bytes
+func readData(pathOrURL string) ([]byte, error) {
+	r, err := openData(pathOrURL)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pathOrURL, err)
+	}
+	
+	if !strings.HasSuffix(dataFileName(pathOrURL), ".gz") && !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid gzip stream: %w", pathOrURL, err)
+	}
+	defer zr.Close()
+	
+	decompressed, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: corrupt gzip stream: %w", pathOrURL, err)
+	}
+	return decompressed, nil
+}
+
+// isYAMLPath reports whether a path or URL names a YAML file (optionally gzipped)
+func isYAMLPath(path string) bool {
+	path = strings.TrimSuffix(strings.ToLower(dataFileName(path)), ".gz")
+	return strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
+
+// unmarshalData decodes JSON, or YAML when the path has a .yaml/.yml extension.
+// YAML is converted to JSON first, so the existing json tags apply to both.
+func unmarshalData(path string, data []byte, v interface{}) error {
+	if isYAMLPath(path) {
+		return yaml.Unmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// LoadBaseData loads base-data.json (or an equivalent .yaml file)
 func LoadBaseData(path string) (*BaseData, error) {
-	data, err := os.ReadFile(path)
+	data, err := readData(path)
 	if err != nil {
 		return nil, err
 	}
 	
 	var baseData BaseData
-	err = json.Unmarshal(data, &baseData)
+	err = unmarshalData(path, data, &baseData)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	baseData.SHA256 = contentDigest(data)
+	
+	if err := ValidateBaseData(&baseData); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	
+	assignScaleIDs(baseData.Scales)
+	aggregateReplicates(baseData.Scales)
+	if problems := scaleProblems(baseData.Scales); len(problems) > 0 {
+		return nil, fmt.Errorf("%s: %w", path, &DataValidationError{Problems: problems})
+	}
+	baseData.Scales, err = dedupeScaleIDs(baseData.Scales, func(s Scale) string { return s.ScaleID })
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 	
 	return &baseData, nil
 }
 
-// LoadTestInput loads test-input.json
+// systemProblems returns the problems with the i-th system of a data file
+func systemProblems(i int, system System) []string {
+	var problems []string
+	label := fmt.Sprintf("systems[%d]", i)
+	if system.SystemID == "" {
+		problems = append(problems, label+": missing SystemID")
+	} else {
+		label = fmt.Sprintf("system %q", system.SystemID)
+	}
+	if system.ScaleFactor == 0 {
+		problems = append(problems, label+": ScaleFactor is zero or missing")
+	}
+	if system.BaseScale == 0 {
+		problems = append(problems, label+": BaseScale is zero or missing")
+	}
+	if !validPowerFormula(system.PowerFormula) {
+		problems = append(problems, fmt.Sprintf("%s: unknown PowerFormula %q (expected %q, %q or %q)",
+			label, system.PowerFormula, PowerGeometric, PowerAffine, PowerLogarithmic))
+	}
+	return problems
+}
+
+// systemWarnings returns the metadata problems of a system that are worth a
+// warning but do not stop the load
+func systemWarnings(system *System) []string {
+	var warnings []string
+	if err := CheckSlopeDimensionConsistency(system, SlopeDimensionTolerance); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	return warnings
+}
+
+// DataValidationError lists every problem found while validating loaded data
+type DataValidationError struct {
+	Problems []string
+}
+
+func (e *DataValidationError) Error() string {
+	return fmt.Sprintf("%d validation problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateBaseData checks for values that json.Unmarshal silently leaves at zero,
+// such as a misspelled ScaleFactor key, and for scales referencing unknown systems
+func ValidateBaseData(baseData *BaseData) error {
+	var problems []string
+	known := make(map[string]bool)
+	
+	for i, system := range baseData.Systems {
+		problems = append(problems, systemProblems(i, system)...)
+		baseData.Systems[i].Warnings = systemWarnings(&system)
+		if system.SystemID != "" {
+			known[system.SystemID] = true
+		}
+	}
+	
+	for i, scale := range baseData.Scales {
+		if !known[scale.System] {
+			problems = append(problems, fmt.Sprintf("scales[%d] (%s): unknown system %q", i, scale.ScaleID, scale.System))
+		}
+	}
+	
+	if len(problems) > 0 {
+		return &DataValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// LoadTestInput loads test-input.json. Scales without a ScaleID are given
+// one by GeneratedScaleID; the answer key must use the same convention for
+// them to match.
 func LoadTestInput(path string) (*TestInput, error) {
-	data, err := os.ReadFile(path)
+	data, err := readData(path)
 	if err != nil {
 		return nil, err
 	}
 	
 	var testInput TestInput
-	err = json.Unmarshal(data, &testInput)
+	err = unmarshalData(path, data, &testInput)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	testInput.Files = []FileDigest{{Path: path, SHA256: contentDigest(data)}}
+	
+	assignScaleIDs(testInput.Scales)
+	aggregateReplicates(testInput.Scales)
+	var problems []string
+	for i, system := range testInput.Systems {
+		problems = append(problems, systemProblems(i, system)...)
+		testInput.Systems[i].Warnings = systemWarnings(&system)
+	}
+	problems = append(problems, scaleProblems(testInput.Scales)...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s: %w", path, &DataValidationError{Problems: problems})
+	}
+	testInput.Scales, err = dedupeScaleIDs(testInput.Scales, func(s Scale) string { return s.ScaleID })
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 	
 	return &testInput, nil
 }
 
+// GeneratedScaleID returns the ScaleID synthesized for a scale that has none:
+// <System>-iter-<Iteration>, e.g. "Koch-iter-4" (or "Koch-iter-2.5" for a
+// fractional IterationFloat)
+func GeneratedScaleID(s *Scale) string {
+	return fmt.Sprintf("%s-iter-%g", s.System, s.EffectiveIteration())
+}
+
+// assignScaleIDs fills in empty ScaleIDs with GeneratedScaleID. Collisions
+// with explicit IDs are left for dedupeScaleIDs to report.
+func assignScaleIDs(scales []Scale) {
+	for i := range scales {
+		if scales[i].ScaleID == "" {
+			scales[i].ScaleID = GeneratedScaleID(&scales[i])
+		}
+	}
+}
+
+// scaleProblems runs Scale.Validate on each scale, labelling every problem
+// with the scale's index and ScaleID
+func scaleProblems(scales []Scale) []string {
+	var problems []string
+	for i := range scales {
+		if err := scales[i].Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("scales[%d] (%s): %v", i, scales[i].ScaleID, err))
+		}
+	}
+	return problems
+}
+
+// aggregateReplicates averages each scale's Replicates into its Measure
+func aggregateReplicates(scales []Scale) {
+	for i := range scales {
+		scales[i].AggregateReplicates()
+	}
+}
+
+// KeepLastDuplicates makes the loaders keep the last of several scales
+// sharing a ScaleID within one file instead of failing
+var KeepLastDuplicates = false
+
+// dedupeScaleIDs checks items for repeated ScaleIDs. Duplicates are an error
+// listing every colliding ID, or with KeepLastDuplicates only the last
+// occurrence of each ID is kept.
+func dedupeScaleIDs[T any](items []T, scaleID func(T) string) ([]T, error) {
+	last := make(map[string]int, len(items))
+	var collisions []string
+	for i, item := range items {
+		id := scaleID(item)
+		if _, dup := last[id]; dup && !slices.Contains(collisions, id) {
+			collisions = append(collisions, id)
+		}
+		last[id] = i
+	}
+	
+	if len(collisions) == 0 {
+		return items, nil
+	}
+	if !KeepLastDuplicates {
+		return nil, fmt.Errorf("duplicate ScaleIDs: %s", strings.Join(collisions, ", "))
+	}
+	
+	kept := make([]T, 0, len(last))
+	for i, item := range items {
+		if last[scaleID(item)] == i {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+// LoadTestInputs loads several test-input files and concatenates their scales.
+// Metadata is taken from the first file. A ScaleID appearing in more than one
+// file is an error naming the conflicting ID and both files.
+func LoadTestInputs(paths []string) (*TestInput, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no test input files given")
+	}
+	
+	var merged *TestInput
+	seen := make(map[string]int)
+	
+	for i, path := range paths {
+		testInput, err := LoadTestInput(path)
+		if err != nil {
+			return nil, err
+		}
+		
+		for _, scale := range testInput.Scales {
+			if first, ok := seen[scale.ScaleID]; ok && first != i {
+				return nil, fmt.Errorf("duplicate ScaleID %q in %s and %s", scale.ScaleID, paths[first], path)
+			}
+			seen[scale.ScaleID] = i
+		}
+		
+		if merged == nil {
+			merged = testInput
+		} else {
+			merged.Scales = append(merged.Scales, testInput.Scales...)
+			merged.Systems = append(merged.Systems, testInput.Systems...)
+			merged.Files = append(merged.Files, testInput.Files...)
+		}
+	}
+	
+	return merged, nil
+}
+
 // LoadAnswerKey loads answer-key.json
 func LoadAnswerKey(path string) (*AnswerKey, error) {
-	data, err := os.ReadFile(path)
+	data, err := readData(path)
 	if err != nil {
 		return nil, err
 	}
 	
 	var answerKey AnswerKey
-	err = json.Unmarshal(data, &answerKey)
+	err = unmarshalData(path, data, &answerKey)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	answerKey.SHA256 = contentDigest(data)
+	
+	answerKey.Scales, err = dedupeScaleIDs(answerKey.Scales, func(s map[string]interface{}) string {
+		id, _ := s["ScaleID"].(string)
+		return id
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 	
 	return &answerKey, nil
 }
 
+// LoadResults loads a previously saved results file
+func LoadResults(path string) (*TestResults, error) {
+	data, err := readData(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	var results TestResults
+	err = unmarshalData(path, data, &results)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	
+	return &results, nil
+}
+
 // SaveResults saves results to JSON file
 func SaveResults(path string, results *TestResults) error {
-	data, err := json.MarshalIndent(results, "", "  ")
+	data, err := json.MarshalIndent(results.canonical(), "", "  ")
 	if err != nil {
 		return err
 	}
@@ -101,11 +489,201 @@ func SaveResults(path string, results *TestResults) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-// BuildSystemsMap creates a lookup map from systems slice
-func BuildSystemsMap(systems []System) SystemsMap {
+// WriteResults writes results to w as indented JSON, as SaveResults does
+func WriteResults(w io.Writer, results *TestResults) error {
+	data, err := json.MarshalIndent(results.canonical(), "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// SortedOutputScales returns a copy of the output maps sorted by System, then
+// iteration, then ScaleID, so results saved from the same data are identical
+// whatever order the input listed the scales in
+func SortedOutputScales(scales []map[string]interface{}) []map[string]interface{} {
+	sorted := slices.Clone(scales)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		systemA, _ := a["System"].(string)
+		systemB, _ := b["System"].(string)
+		if systemA != systemB {
+			return systemA < systemB
+		}
+		if iterA, iterB := OutputIteration(a), OutputIteration(b); iterA != iterB {
+			return iterA < iterB
+		}
+		idA, _ := a["ScaleID"].(string)
+		idB, _ := b["ScaleID"].(string)
+		return idA < idB
+	})
+	return sorted
+}
+
+// canonical returns a copy of the results with the scales in
+// SortedOutputScales order and negative zeros written as 0, for saving
+func (r *TestResults) canonical() *TestResults {
+	c := *r
+	c.Scales = SortedOutputScales(r.Scales)
+	for i, m := range c.Scales {
+		c.Scales[i] = withoutNegativeZeros(m)
+	}
+	if r.Fits != nil {
+		c.Fits = make(map[string]FitResult, len(r.Fits))
+		for id, f := range r.Fits {
+			f.Slope = positiveZero(f.Slope)
+			f.Intercept = positiveZero(f.Intercept)
+			f.RSquared = positiveZero(f.RSquared)
+			f.SlopeStdErr = positiveZero(f.SlopeStdErr)
+			f.MeanLogScale = positiveZero(f.MeanLogScale)
+			c.Fits[id] = f
+		}
+	}
+	return &c
+}
+
+// positiveZero returns v with negative zero, which rounding a tiny negative
+// value produces and JSON writes as "-0", replaced by 0
+func positiveZero(v float64) float64 {
+	if v == 0 {
+		return 0
+	}
+	return v
+}
+
+// withoutNegativeZeros returns m, or a copy of it with positiveZero applied
+// to its float values when any of them is negative zero
+func withoutNegativeZeros(m map[string]interface{}) map[string]interface{} {
+	var c map[string]interface{}
+	for k, v := range m {
+		if f, ok := v.(float64); ok && f == 0 && math.Signbit(f) {
+			if c == nil {
+				c = maps.Clone(m)
+			}
+			c[k] = 0.0
+		}
+	}
+	if c == nil {
+		return m
+	}
+	return c
+}
+
+// CSVColumns is the stable column order used by SaveResultsCSV
+var CSVColumns = []string{
+	"ScaleID", "System", "Iteration", "Measure", "BaseScale", "ScaleFactor",
+	"ScaleFactorPower", "Scale", "LogScale", "LogMeasure", "IsProjected",
+}
+
+// SaveResultsCSV saves results to a CSV file with one row per scale
+func SaveResultsCSV(path string, results *TestResults) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := WriteResultsCSV(f, results); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WriteResultsCSV writes results to out as CSV with one row per scale
+func WriteResultsCSV(out io.Writer, results *TestResults) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(CSVColumns); err != nil {
+		return err
+	}
+
+	for _, scale := range SortedOutputScales(results.Scales) {
+		row := make([]string, len(CSVColumns))
+		for i, col := range CSVColumns {
+			row[i] = formatCSVValue(scale[col])
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// SaveResultsJSONL saves results as JSON lines: one compact object per scale,
+// carrying the run's platform and timestamp alongside the scale's fields
+func SaveResultsJSONL(path string, results *TestResults) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, scale := range SortedOutputScales(results.Scales) {
+		line := make(map[string]interface{}, len(scale)+2)
+		for k, v := range scale {
+			line[k] = v
+		}
+		line["platform"] = results.Platform
+		line["timestamp"] = results.Timestamp
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// formatCSVValue renders a scale field for CSV, keeping full float precision
+func formatCSVValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// BuildSystemsMap creates a lookup map from systems slice, and binds each of
+// the given scales to its system (see Scale.BindSystem) so computing them
+// needs no further lookups. Scales of unknown systems are left unbound and
+// fail when computed.
+func BuildSystemsMap(systems []System, scales ...[]Scale) SystemsMap {
 	m := make(SystemsMap)
 	for i := range systems {
 		m[systems[i].SystemID] = &systems[i]
 	}
+	for _, group := range scales {
+		for i := range group {
+			group[i].BindSystem(m)
+		}
+	}
 	return m
 }
+
+// MergeSystems returns base with each override replacing the system of the
+// same SystemID; overrides for systems not in base are appended. When the
+// same SystemID is overridden more than once the last override wins.
+func MergeSystems(base, overrides []System) []System {
+	merged := slices.Clone(base)
+	for _, system := range overrides {
+		i := slices.IndexFunc(merged, func(s System) bool { return s.SystemID == system.SystemID })
+		if i < 0 {
+			merged = append(merged, system)
+		} else {
+			merged[i] = system
+		}
+	}
+	return merged
+}
@@ -0,0 +1,93 @@
+package rulebook
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSaveResultsStable saves the same results twice, once with the scales
+// reversed, and expects byte-identical files in canonical scale order
+func TestSaveResultsStable(t *testing.T) {
+	computed, _, _ := loadComputedTestData(t)
+	reversed := slices.Clone(computed)
+	slices.Reverse(reversed)
+
+	dir := t.TempDir()
+	var saved [][]byte
+	for i, scales := range [][]map[string]interface{}{computed, reversed} {
+		path := filepath.Join(dir, fmt.Sprintf("results%d.json", i))
+		results := &TestResults{Platform: "golang", Timestamp: "2026-01-01T00:00:00Z", Scales: scales}
+		if err := SaveResults(path, results); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, data)
+	}
+	if !bytes.Equal(saved[0], saved[1]) {
+		t.Fatal("results saved from reordered scales differ")
+	}
+
+	loaded, err := LoadResults(filepath.Join(dir, "results0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(loaded.Scales); i++ {
+		a, b := loaded.Scales[i-1], loaded.Scales[i]
+		if a["System"].(string) > b["System"].(string) ||
+			a["System"] == b["System"] && OutputIteration(a) > OutputIteration(b) {
+			t.Errorf("scale %v saved before %v", a["ScaleID"], b["ScaleID"])
+		}
+	}
+}
+
+// The slope/dimension check runs at load for every system, whatever its
+// Class, and only warns
+func TestLoadBaseDataWarnsOnSlopeDimensionMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base-data.json")
+	data := `{"systems": [
+		{"SystemID": "Flipped", "BaseScale": 1, "ScaleFactor": 0.5, "FractalDimension": 1.585, "TheoreticalLogLogSlope": 1.585},
+		{"SystemID": "Rounded", "Class": "fractal", "BaseScale": 1, "ScaleFactor": 0.5, "FractalDimension": 1.5849625, "TheoreticalLogLogSlope": -1.585}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	baseData, err := LoadBaseData(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := baseData.Systems[0].Warnings; len(warnings) != 1 {
+		t.Errorf("Flipped: got warnings %q, want one slope/dimension warning", warnings)
+	}
+	if warnings := baseData.Systems[1].Warnings; len(warnings) != 0 {
+		t.Errorf("Rounded: got warnings %q within the default tolerance", warnings)
+	}
+}
+
+func TestWriteResultsNoNegativeZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	results := &TestResults{
+		Platform: "golang",
+		Scales:   []map[string]interface{}{{"ScaleID": "Koch_0", "System": "Koch", "Iteration": 0, "LogScale": negZero}},
+		Fits:     map[string]FitResult{"Koch": {Slope: -0.26, Intercept: negZero}},
+	}
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "-0,") || strings.Contains(buf.String(), "-0\n") {
+		t.Errorf("negative zero written:\n%s", buf.String())
+	}
+	if !math.Signbit(results.Scales[0]["LogScale"].(float64)) {
+		t.Error("WriteResults modified the caller's scale")
+	}
+}
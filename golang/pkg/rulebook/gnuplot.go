@@ -0,0 +1,71 @@
+//
+// Gnuplot Export
+//
+// Writes each system's log-log points as a whitespace-delimited data file
+// for scripting figures in gnuplot
+//
+
+package rulebook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SaveGnuplotData writes <dir>/<SystemID>.dat for each system with columns
+// logScale, logMeasure and isProjected (1 or 0), in iteration order. A header
+// comment gives the theoretical slope and the intercept of that line best
+// fitting the actual points, as a gnuplot function ready to plot. Systems
+// missing from systems are skipped, as are points without valid logs.
+func SaveGnuplotData(dir string, scalesBySystem map[string][]map[string]interface{}, systems SystemsMap) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for systemID, scales := range scalesBySystem {
+		system, ok := systems[systemID]
+		if !ok {
+			continue
+		}
+
+		points := ExtractPlotPoints(scales)
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Iteration < points[j].Iteration })
+
+		slope := system.TheoreticalLogLogSlope
+		var sum float64
+		actuals := 0
+		for _, p := range points {
+			if !p.IsProjected && !p.IsInterpolated {
+				sum += p.Y - slope*p.X
+				actuals++
+			}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s (%s), %s log-log\n", system.DisplayName, system.SystemID, system.LogLabel())
+		fmt.Fprintf(&b, "# theoretical slope = %g\n", slope)
+		if actuals > 0 {
+			intercept := sum / float64(actuals)
+			fmt.Fprintf(&b, "# intercept = %g\n", intercept)
+			fmt.Fprintf(&b, "# f(x) = %g %+g*x\n", intercept, slope)
+		} else {
+			b.WriteString("# intercept = n/a (no actual points)\n")
+		}
+		b.WriteString("# logScale logMeasure isProjected\n")
+		for _, p := range points {
+			projected := 0
+			if p.IsProjected {
+				projected = 1
+			}
+			fmt.Fprintf(&b, "%g %g %d\n", p.X, p.Y, projected)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, systemID+".dat"), []byte(b.String()), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
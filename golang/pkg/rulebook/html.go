@@ -0,0 +1,137 @@
+//
+// HTML Report
+//
+// Self-contained HTML report with per-system tables and inline SVG plots,
+// styled inline so the file can be shared and opened offline
+//
+
+package rulebook
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strings"
+)
+
+// HTML report styling
+const (
+	htmlPassColor  = "#2ca02c"
+	htmlFailColor  = "#d62728"
+	htmlFailRow    = "#fde0e0"
+	htmlCellStyle  = "padding:4px 10px;border-bottom:1px solid #ddd;text-align:right"
+	htmlPlotWidth  = 640
+	htmlPlotHeight = 420
+)
+
+// SaveHTMLReport writes a single HTML file with a pass/fail summary, then a
+// table and an inline SVG log-log plot for each system. Rows of scales listed
+// in failures are highlighted.
+func SaveHTMLReport(path string, systems SystemsMap, allScales []map[string]interface{}, failures []ValidationResult) error {
+	failed := make(map[string]bool, len(failures))
+	for _, f := range failures {
+		failed[f.ScaleID] = true
+	}
+
+	bySystem := make(map[string][]map[string]interface{})
+	for _, s := range allScales {
+		systemID, _ := s["System"].(string)
+		bySystem[systemID] = append(bySystem[systemID], s)
+	}
+	systemIDs := make([]string, 0, len(bySystem))
+	for id := range bySystem {
+		systemIDs = append(systemIDs, id)
+	}
+	sort.Strings(systemIDs)
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>Power Laws &amp; Fractals - Go Test Report</title>\n</head>\n")
+	b.WriteString(`<body style="font-family:sans-serif;max-width:900px;margin:2em auto;color:#222">` + "\n")
+	b.WriteString("<h1>Power Laws &amp; Fractals - Go Test Report</h1>\n")
+
+	writeHTMLSummary(&b, failures)
+
+	for _, id := range systemIDs {
+		system, ok := systems[id]
+		if !ok {
+			continue
+		}
+		scales := bySystem[id]
+		sort.SliceStable(scales, func(i, j int) bool { return OutputIteration(scales[i]) < OutputIteration(scales[j]) })
+
+		fmt.Fprintf(&b, "<h2>%s</h2>\n", html.EscapeString(system.DisplayName))
+		fmt.Fprintf(&b, "<p style=\"color:#666\">%s · class %s · theoretical slope %.3f</p>\n",
+			html.EscapeString(system.SystemID), html.EscapeString(system.Class), system.TheoreticalLogLogSlope)
+		writeHTMLTable(&b, scales, system, failed)
+		b.WriteString(RenderSVGPlot(scales, system, nil, htmlPlotWidth, htmlPlotHeight))
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// writeHTMLSummary writes the colored pass/fail banner and the failure list
+func writeHTMLSummary(b *strings.Builder, failures []ValidationResult) {
+	if len(failures) == 0 {
+		fmt.Fprintf(b, `<p style="background:%s;color:white;padding:10px;border-radius:4px">✓ All validated scales passed</p>`+"\n", htmlPassColor)
+		return
+	}
+
+	fmt.Fprintf(b, `<p style="background:%s;color:white;padding:10px;border-radius:4px">✗ %d scale(s) failed validation</p>`+"\n",
+		htmlFailColor, len(failures))
+	b.WriteString("<ul>\n")
+	for _, f := range failures {
+		fmt.Fprintf(b, "<li><b>%s</b><ul>\n", html.EscapeString(f.ScaleID))
+		for _, m := range f.Mismatches {
+			fmt.Fprintf(b, "<li>%s</li>\n", html.EscapeString(m))
+		}
+		b.WriteString("</ul></li>\n")
+	}
+	b.WriteString("</ul>\n")
+}
+
+// writeHTMLTable writes one system's scales as a table, highlighting failed rows
+func writeHTMLTable(b *strings.Builder, scales []map[string]interface{}, system *System, failed map[string]bool) {
+	logLabel := system.LogLabel()
+	b.WriteString(`<table style="border-collapse:collapse;margin-bottom:1em">` + "\n<tr>")
+	for _, h := range []string{"Iter", system.WithMeasureUnit("Measure"), system.WithScaleUnit("Scale"), logLabel + "(Scale)", logLabel + "(Measure)", "Type"} {
+		fmt.Fprintf(b, `<th style="%s">%s</th>`, htmlCellStyle, html.EscapeString(h))
+	}
+	b.WriteString("</tr>\n")
+
+	for _, s := range scales {
+		scaleID, _ := s["ScaleID"].(string)
+		kind := "actual"
+		if isProj, _ := s["IsProjected"].(bool); isProj {
+			kind = "projected"
+		} else if isInterp, _ := s["IsInterpolated"].(bool); isInterp {
+			kind = "interpolated"
+		}
+
+		rowStyle := ""
+		if failed[scaleID] {
+			rowStyle = fmt.Sprintf(` style="background:%s"`, htmlFailRow)
+			kind += " ✗"
+		}
+		fmt.Fprintf(b, `<tr%s title="%s">`, rowStyle, html.EscapeString(scaleID))
+		for _, v := range []interface{}{OutputIteration(s), s["Measure"], s["Scale"], s["LogScale"], s["LogMeasure"], kind} {
+			fmt.Fprintf(b, `<td style="%s">%s</td>`, htmlCellStyle, html.EscapeString(formatHTMLValue(v)))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+}
+
+// formatHTMLValue formats a table cell, showing "n/a" for missing (nil) values
+func formatHTMLValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "n/a"
+	case float64:
+		return fmt.Sprintf("%g", x)
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,193 @@
+package rulebook
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// TestRecomputeRoundTrip checks that resetting and recomputing every scale of
+// the repo's test data reproduces its output exactly, and that a reset picks
+// up a changed input
+func TestRecomputeRoundTrip(t *testing.T) {
+	baseData, err := LoadBaseData(testDataPath("base-data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testInput, err := LoadTestInput(testDataPath("test-input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	systems := BuildSystemsMap(baseData.Systems, baseData.Scales, testInput.Scales)
+
+	for _, scales := range [][]Scale{baseData.Scales, testInput.Scales} {
+		for i := range scales {
+			s := &scales[i]
+			if err := s.CalculateAllFields(systems); err != nil {
+				t.Fatal(err)
+			}
+			before := s.ToOutputMap()
+
+			s.ResetComputed()
+			if err := s.CalculateAllFields(systems); err != nil {
+				t.Fatal(err)
+			}
+			if after := s.ToOutputMap(); !reflect.DeepEqual(after, before) {
+				t.Errorf("%s changed on recompute:\n%v\n%v", s.ScaleID, before, after)
+			}
+		}
+	}
+
+	s := testInput.Scales[0].Clone()
+	*s.Measure *= 10
+	s.ResetComputed()
+	if err := s.CalculateAllFields(systems); err != nil {
+		t.Fatal(err)
+	}
+	want := testInput.Scales[0].CalculateLogMeasure() + 1
+	if got := s.CalculateLogMeasure(); math.Abs(got-want) > 1e-12 {
+		t.Errorf("LogMeasure after a 10x Measure = %g, want %g", got, want)
+	}
+}
+
+// TestPowerFormulaWithoutBinding checks that scales looked up through the
+// SystemsMap, including projected and interpolated ones, follow the system's
+// PowerFormula rather than the geometric default
+func TestPowerFormulaWithoutBinding(t *testing.T) {
+	for _, system := range []*System{
+		{SystemID: "Affine", ScaleFactor: 2, BaseScale: 1, PowerFormula: PowerAffine, PowerOffset: 3, TheoreticalLogLogSlope: -1},
+		{SystemID: "Log", ScaleFactor: 2, BaseScale: 1, PowerFormula: PowerLogarithmic, TheoreticalLogLogSlope: -1},
+	} {
+		systems := BuildSystemsMap([]System{*system})
+
+		unbound := &Scale{ScaleID: system.SystemID + "_2", System: system.SystemID, Iteration: 2, Measure: floatPtr(1)}
+		if err := unbound.CalculateAllFields(systems); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := unbound.GetScaleFactorPower(), system.ScaleFactorPower(2); got != want {
+			t.Errorf("%s: unbound ScaleFactorPower at iteration 2 = %g, want %g", system.SystemID, got, want)
+		}
+
+		var actuals []*Scale
+		for _, iter := range []int{0, 3} {
+			s := &Scale{ScaleID: fmt.Sprintf("%s_%d", system.SystemID, iter), System: system.SystemID, Iteration: iter, Measure: floatPtr(1)}
+			if err := s.CalculateAllFields(systems); err != nil {
+				t.Fatal(err)
+			}
+			actuals = append(actuals, s)
+		}
+
+		generated := append(ProjectScales(system, actuals, []int{4}, AnchorFirst), InterpolateMissingScales(system, actuals)...)
+		if len(generated) != 3 {
+			t.Fatalf("%s: got %d projected and interpolated scales, want 3", system.SystemID, len(generated))
+		}
+		for _, s := range generated {
+			if got, want := s.GetScaleFactorPower(), system.ScaleFactorPower(float64(s.Iteration)); got != want {
+				t.Errorf("%s: iteration %d ScaleFactorPower = %g, want %g", system.SystemID, s.Iteration, got, want)
+			}
+		}
+	}
+}
+
+// benchmarkScales returns a scale per iteration of a single system, with the
+// system map they are computed against
+func benchmarkScales() ([]System, []Scale) {
+	systems := []System{{
+		SystemID:               "Koch",
+		ScaleFactor:            1.0 / 3,
+		BaseScale:              1,
+		TheoreticalLogLogSlope: -0.26186,
+	}}
+	return systems, GenerateSyntheticScales(&systems[0], 64, 0, 1)
+}
+
+// BenchmarkCalculateAllFields compares computing scales that look their system
+// up in the SystemsMap with scales bound to it by BuildSystemsMap
+func BenchmarkCalculateAllFields(b *testing.B) {
+	b.Run("lookup", func(b *testing.B) {
+		systems, scales := benchmarkScales()
+		m := BuildSystemsMap(systems)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			for i := range scales {
+				scales[i].ResetComputed()
+				scales[i].CalculateAllFields(m)
+			}
+		}
+	})
+	b.Run("bound", func(b *testing.B) {
+		systems, scales := benchmarkScales()
+		m := BuildSystemsMap(systems, scales)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			for i := range scales {
+				scales[i].ResetComputed()
+				scales[i].CalculateAllFields(m)
+			}
+		}
+	})
+}
+
+// exactPower returns base^n computed in 256-bit precision and rounded once
+// to the nearest float64
+func exactPower(base float64, n int) float64 {
+	b := new(big.Float).SetPrec(256).SetFloat64(base)
+	result := new(big.Float).SetPrec(256).SetFloat64(1)
+	for i := 0; i < n; i++ {
+		result.Mul(result, b)
+	}
+	f, _ := result.Float64()
+	return f
+}
+
+// TestPowBySquaringPrecision compares exponentiation by squaring and math.Pow
+// against the correctly rounded integer power. Both must stay far inside the
+// 6-decimal output precision; the worst ULP errors are logged for comparison.
+func TestPowBySquaringPrecision(t *testing.T) {
+	var worstSquaring, worstPow uint64
+	for _, base := range []float64{0.5, 1.0 / 3, 2, 3, 1.1, 0.9, 1.0 / 1.7} {
+		for n := 0; n <= 64; n++ {
+			exact := exactPower(base, n)
+			squared := powBySquaring(base, uint64(n))
+			pow := math.Pow(base, float64(n))
+			if rel := math.Abs(squared-exact) / exact; rel > 1e-13 {
+				t.Errorf("powBySquaring(%g, %d) = %g, exact %g (relative error %g)", base, n, squared, exact, rel)
+			}
+			if rel := math.Abs(pow-exact) / exact; rel > 1e-13 {
+				t.Errorf("math.Pow(%g, %d) = %g, exact %g (relative error %g)", base, n, pow, exact, rel)
+			}
+			worstSquaring = max(worstSquaring, ULPDistance(squared, exact))
+			worstPow = max(worstPow, ULPDistance(pow, exact))
+		}
+	}
+	t.Logf("worst error: powBySquaring %d ULPs, math.Pow %d ULPs", worstSquaring, worstPow)
+}
+
+func TestPowerUsesSquaringOnlyForIntegers(t *testing.T) {
+	defer func(old bool) { PowerBySquaring = old }(PowerBySquaring)
+	PowerBySquaring = true
+	if got, want := power(1.0/3, 7), powBySquaring(1.0/3, 7); got != want {
+		t.Errorf("power(1/3, 7) = %g, want squaring result %g", got, want)
+	}
+	if got, want := power(2, 2.5), math.Pow(2, 2.5); got != want {
+		t.Errorf("power(2, 2.5) = %g, want math.Pow result %g", got, want)
+	}
+	if got, want := power(2, -3), math.Pow(2, -3); got != want {
+		t.Errorf("power(2, -3) = %g, want math.Pow result %g", got, want)
+	}
+}
+
+func BenchmarkPower(b *testing.B) {
+	b.Run("math.Pow", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			math.Pow(1.0/3, float64(n%64))
+		}
+	})
+	b.Run("squaring", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			powBySquaring(1.0/3, uint64(n%64))
+		}
+	})
+}
@@ -0,0 +1,102 @@
+//go:build parquet
+
+//
+// Parquet Export
+//
+// Writes computed scales as a Parquet file for data-lake ingestion. Built
+// only with -tags parquet so the default build needs no Parquet dependency.
+//
+
+package rulebook
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// SaveResultsParquet saves results to a Parquet file with one row per scale.
+// Columns are named after the results JSON fields and typed from their
+// values (string, double, int64 or boolean); every column is optional, so
+// fields a scale lacks (or "n/a" logs) are written as nulls.
+func SaveResultsParquet(path string, results *TestResults) error {
+	scales := SortedOutputScales(results.Scales)
+	group, err := parquetGroup(scales)
+	if err != nil {
+		return err
+	}
+	schema := parquet.NewSchema("results", group)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	columns := schema.Columns()
+	rows := make([]parquet.Row, len(scales))
+	for i, scale := range scales {
+		row := make(parquet.Row, len(columns))
+		for j, column := range columns {
+			v, ok := scale[column[0]]
+			if !ok || v == nil {
+				row[j] = parquet.Value{}.Level(0, 0, j)
+				continue
+			}
+			if n, isInt := v.(int); isInt {
+				v = int64(n)
+			}
+			row[j] = parquet.ValueOf(v).Level(0, 1, j)
+		}
+		rows[i] = row
+	}
+
+	w := parquet.NewWriter(f, schema)
+	if _, err := w.WriteRows(rows); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// parquetGroup builds the Parquet schema for the union of the scales'
+// fields, typing each column from its first non-nil value
+func parquetGroup(scales []map[string]interface{}) (parquet.Group, error) {
+	var names []string
+	kinds := make(map[string]interface{})
+	for _, scale := range scales {
+		for name, v := range scale {
+			if _, seen := kinds[name]; !seen {
+				names = append(names, name)
+				kinds[name] = nil
+			}
+			if kinds[name] == nil {
+				kinds[name] = v
+			}
+		}
+	}
+	sort.Strings(names)
+
+	group := make(parquet.Group, len(names))
+	for _, name := range names {
+		var node parquet.Node
+		switch v := kinds[name].(type) {
+		case string:
+			node = parquet.String()
+		case float64, nil:
+			node = parquet.Leaf(parquet.DoubleType)
+		case int, int64:
+			node = parquet.Int(64)
+		case bool:
+			node = parquet.Leaf(parquet.BooleanType)
+		default:
+			return nil, fmt.Errorf("column %s: unsupported value type %T", name, v)
+		}
+		group[name] = parquet.Optional(node)
+	}
+	return group, nil
+}
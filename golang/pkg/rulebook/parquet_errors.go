@@ -0,0 +1,16 @@
+//
+// Parquet Errors
+//
+// Declared without a build tag so callers can test for it in every build,
+// with or without -tags parquet
+//
+
+package rulebook
+
+import (
+	"errors"
+)
+
+// ErrParquetUnsupported is returned by SaveResultsParquet in builds without
+// the parquet build tag
+var ErrParquetUnsupported = errors.New("Parquet export not built in (rebuild with -tags parquet)")
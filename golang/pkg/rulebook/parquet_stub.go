@@ -0,0 +1,9 @@
+//go:build !parquet
+
+package rulebook
+
+// SaveResultsParquet reports ErrParquetUnsupported: this binary was built
+// without -tags parquet
+func SaveResultsParquet(path string, results *TestResults) error {
+	return ErrParquetUnsupported
+}
@@ -0,0 +1,412 @@
+//
+// Pipeline
+//
+// Library entry point for the load → compute → validate flow of the
+// unified testing protocol, independent of the test runner's printing
+//
+
+package rulebook
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// PipelineConfig holds the inputs for a pipeline run
+type PipelineConfig struct {
+	BaseDataPath   string
+	TestInputPaths []string
+	AnswerKeyPath  string
+
+	// Platform is recorded in the results (default "golang")
+	Platform string
+	// Workers is the number of goroutines used to compute and validate scales
+	// (0 = one per CPU)
+	Workers int
+	// Tolerances overrides the validation tolerance per field (nil = default)
+	Tolerances FieldTolerances
+	// Strict fails every answer-key scale that was not computed
+	Strict bool
+	// MaxFailures stops validating against the primary answer key once this
+	// many scales have failed (0 = no limit); see PipelineRun.StoppedEarly
+	MaxFailures int
+	// TraceScaleID selects a scale whose calculation steps are sent to Trace
+	TraceScaleID string
+	Trace        TraceFunc
+	// Filter limits the scales that are computed and validated
+	Filter ScaleFilter
+	// StreamAnswerKey reads the answer key element by element to save memory
+	// on very large files; PipelineRun.AnswerKey is then left nil
+	StreamAnswerKey bool
+	// Progress, when set, receives progress while scales are computed, every
+	// ProgressEvery scales (0 = DefaultProgressEvery); the test and base
+	// scales are counted together, and runs smaller than ProgressEvery are
+	// not reported
+	Progress      ProgressFunc
+	ProgressEvery int
+	// RecordTiming adds each system's ComputeMillis to the results
+	RecordTiming bool
+	// Gates are further answer keys validated with their own tolerances,
+	// reported in PipelineRun.Gates
+	Gates []AnswerKeyGate
+}
+
+// AnswerKeyGate is an answer key validated with its own tolerance, such as the
+// "tight" and "loose" keys of different CI gates
+type AnswerKeyGate struct {
+	Name string
+	Path string
+	// Tolerance is the absolute tolerance for every computed field (0 = Tolerance)
+	Tolerance float64
+	// Required marks gates that decide whether the run passes
+	Required bool
+}
+
+// EffectiveTolerance returns the gate's tolerance, or the default Tolerance
+func (g AnswerKeyGate) EffectiveTolerance() float64 {
+	if g.Tolerance > 0 {
+		return g.Tolerance
+	}
+	return Tolerance
+}
+
+// StrictestRequiredGate returns the required gate with the smallest
+// tolerance (the first on ties), or false when no gate is required
+func StrictestRequiredGate(gates []AnswerKeyGate) (AnswerKeyGate, bool) {
+	var strictest AnswerKeyGate
+	found := false
+	for _, g := range gates {
+		if g.Required && (!found || g.EffectiveTolerance() < strictest.EffectiveTolerance()) {
+			strictest, found = g, true
+		}
+	}
+	return strictest, found
+}
+
+// GateResult is the validation outcome of one AnswerKeyGate
+type GateResult struct {
+	Gate      AnswerKeyGate
+	PassCount int
+	FailCount int
+	Failures  []ValidationResult
+}
+
+// Passed reports whether the scale with the given ID passed this gate
+func (r GateResult) Passed(scaleID string) bool {
+	for _, f := range r.Failures {
+		if f.ScaleID == scaleID {
+			return false
+		}
+	}
+	return true
+}
+
+// ScaleFilter selects scales by system and iteration range. The zero value
+// selects everything.
+type ScaleFilter struct {
+	// Systems lists the system IDs to keep (empty = all)
+	Systems []string
+	// IterMin and IterMax bound the iteration inclusively (nil = unbounded)
+	IterMin *int
+	IterMax *int
+}
+
+// Matches reports whether a scale of the given system and iteration is selected
+func (f ScaleFilter) Matches(system string, iteration float64) bool {
+	if len(f.Systems) > 0 && !slices.Contains(f.Systems, system) {
+		return false
+	}
+	if f.IterMin != nil && iteration < float64(*f.IterMin) {
+		return false
+	}
+	if f.IterMax != nil && iteration > float64(*f.IterMax) {
+		return false
+	}
+	return true
+}
+
+// check returns an error for any filtered system that is not defined
+func (f ScaleFilter) check(systems SystemsMap) error {
+	for _, id := range f.Systems {
+		if _, err := systems.lookup(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterScales returns the scales selected by the filter
+func (f ScaleFilter) filterScales(scales []Scale) []Scale {
+	kept := scales[:0:0]
+	for _, s := range scales {
+		if f.Matches(s.System, s.EffectiveIteration()) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// filterMaps returns the output or answer-key maps selected by the filter
+func (f ScaleFilter) filterMaps(scales []map[string]interface{}) []map[string]interface{} {
+	kept := scales[:0:0]
+	for _, m := range scales {
+		system, _ := m["System"].(string)
+		if f.Matches(system, OutputIteration(m)) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// PipelineRun holds everything produced by a pipeline run, for callers that
+// need more than the results and validation outcomes
+type PipelineRun struct {
+	BaseData  *BaseData
+	TestInput *TestInput
+	AnswerKey *AnswerKey
+	Systems   SystemsMap
+
+	// Results holds the computed test scales (the validated output)
+	Results *TestResults
+	// AllScales holds computed base and test scales for visualization
+	AllScales []map[string]interface{}
+	// ScalesBySystem groups the computed scales of known systems
+	ScalesBySystem map[string][]*Scale
+
+	PassCount     int
+	FailCount     int
+	Failures      []ValidationResult
+	ComputeErrors []error
+	// StoppedEarly is set when validation stopped at PipelineConfig.MaxFailures,
+	// leaving some scales unvalidated
+	StoppedEarly bool
+
+	// Gates holds the outcome of each PipelineConfig.Gates entry, in order
+	Gates []GateResult
+
+	// ComputeTimes is the time spent computing each system's base and test
+	// scales, summed over scales (so it is CPU time when computing in parallel)
+	ComputeTimes map[string]time.Duration
+}
+
+// RunPipeline loads the data files, computes derived values for the test
+// scales, and validates them against the answer key. Scales that could not be
+// computed are left out of the results and reported together in the error,
+// which is returned alongside the (partial) results.
+func RunPipeline(cfg PipelineConfig) (*TestResults, []ValidationResult, error) {
+	run, err := ExecutePipeline(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return run.Results, run.Failures, errors.Join(run.ComputeErrors...)
+}
+
+// ExecutePipeline runs the pipeline and returns the full PipelineRun.
+// The error is non-nil only when a data file could not be loaded.
+func ExecutePipeline(cfg PipelineConfig) (*PipelineRun, error) {
+	platform := cfg.Platform
+	if platform == "" {
+		platform = "golang"
+	}
+
+	baseData, err := LoadBaseData(cfg.BaseDataPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load base data: %w", err)
+	}
+
+	testInput, err := LoadTestInputs(cfg.TestInputPaths)
+	if err != nil {
+		return nil, fmt.Errorf("could not load test input: %w", err)
+	}
+
+	run := &PipelineRun{
+		BaseData:  baseData,
+		TestInput: testInput,
+		Systems:   BuildSystemsMap(MergeSystems(baseData.Systems, testInput.Systems), baseData.Scales, testInput.Scales),
+	}
+
+	// Filter after loading so only the selected scales are computed and validated
+	if err := cfg.Filter.check(run.Systems); err != nil {
+		return nil, fmt.Errorf("invalid scale filter: %w", err)
+	}
+	baseData.Scales = cfg.Filter.filterScales(baseData.Scales)
+	testInput.Scales = cfg.Filter.filterScales(testInput.Scales)
+
+	answerKey, err := loadAnswerKeyIndex(cfg.AnswerKeyPath, cfg, run)
+	if err != nil {
+		return nil, fmt.Errorf("could not load answer key: %w", err)
+	}
+
+	if cfg.TraceScaleID != "" && cfg.Trace != nil {
+		setTrace(cfg.TraceScaleID, cfg.Trace, baseData.Scales, testInput.Scales)
+	}
+
+	// Compute derived values for test scales (the validated output)
+	total := len(testInput.Scales) + len(baseData.Scales)
+	every := cfg.ProgressEvery
+	if every <= 0 {
+		every = DefaultProgressEvery
+	}
+	if total < every {
+		cfg.Progress = nil
+	}
+	run.ComputeTimes = make(map[string]time.Duration)
+	testScales, testErrors := computeScales(testInput.Scales, run.Systems, cfg, offsetProgress(cfg.Progress, 0, total), run.ComputeTimes)
+	run.Results = &TestResults{
+		Platform:  platform,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Scales:    testScales,
+		Provenance: &Provenance{
+			BaseData:    FileDigest{Path: cfg.BaseDataPath, SHA256: baseData.SHA256},
+			TestInputs:  testInput.Files,
+			AnswerKey:   FileDigest{Path: cfg.AnswerKeyPath, SHA256: answerKey.SHA256},
+			ToolVersion: ToolVersion,
+		},
+	}
+
+	// Compute base scales so the full series can be visualized
+	baseScales, baseErrors := computeScales(baseData.Scales, run.Systems, cfg,
+		offsetProgress(cfg.Progress, len(testInput.Scales), total), run.ComputeTimes)
+	run.AllScales = append(baseScales, testScales...)
+	run.ComputeErrors = append(baseErrors, testErrors...)
+	run.ScalesBySystem = groupScalesBySystem(run.Systems, baseData.Scales, testInput.Scales)
+	run.Results.Fits = fitSystems(run.ScalesBySystem)
+	if cfg.RecordTiming {
+		run.Results.ComputeMillis = make(map[string]float64, len(run.ComputeTimes))
+		for id, d := range run.ComputeTimes {
+			run.Results.ComputeMillis[id] = float64(d) / float64(time.Millisecond)
+		}
+	}
+
+	// Validate against answer key
+	run.PassCount, run.FailCount, run.Failures, run.StoppedEarly = validateRun(cfg, run, testScales, answerKey,
+		cfg.Tolerances, cfg.MaxFailures)
+
+	for _, gate := range cfg.Gates {
+		index, err := loadAnswerKeyIndex(gate.Path, cfg, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not load answer key %s: %w", gate.Name, err)
+		}
+		result := GateResult{Gate: gate}
+		// Gates are validated in full, since GateResult.Passed reads
+		// unvalidated scales as passing
+		result.PassCount, result.FailCount, result.Failures, _ = validateRun(cfg, run, testScales, index,
+			UniformTolerances(gate.EffectiveTolerance()), 0)
+		run.Gates = append(run.Gates, result)
+	}
+
+	return run, nil
+}
+
+// validateRun validates the test scales against one answer key across
+// cfg.Workers goroutines, stopping after maxFailures failures (0 = no limit),
+// and adds the answer-key scales
+// that were not computed as failures in strict mode
+func validateRun(cfg PipelineConfig, run *PipelineRun, testScales []map[string]interface{}, index *AnswerKeyIndex,
+	tolerances FieldTolerances, maxFailures int) (int, int, []ValidationResult, bool) {
+
+	passCount, failCount, failures, stopped := ValidateAgainstIndexLimit(testScales, index, tolerances, maxFailures, cfg.Workers)
+	if cfg.Strict && !stopped {
+		missing := FindMissingInIndex(run.AllScales, index)
+		failCount += len(missing)
+		failures = append(failures, missing...)
+	}
+	return passCount, failCount, failures, stopped
+}
+
+// loadAnswerKeyIndex loads and filters an answer key, streaming it when
+// configured or loading it whole. A non-nil run keeps the loaded key in
+// run.AnswerKey (left nil when streaming).
+func loadAnswerKeyIndex(path string, cfg PipelineConfig, run *PipelineRun) (*AnswerKeyIndex, error) {
+	if cfg.StreamAnswerKey {
+		return StreamAnswerKey(path, cfg.Filter)
+	}
+
+	answerKey, err := LoadAnswerKey(path)
+	if err != nil {
+		return nil, err
+	}
+	answerKey.Scales = cfg.Filter.filterMaps(answerKey.Scales)
+	if run != nil {
+		run.AnswerKey = answerKey
+	}
+	return IndexAnswerKey(answerKey), nil
+}
+
+// computeScales computes derived values for each scale, collecting the output maps
+// of successful scales and the errors of scales that could not be computed, and
+// adding the time spent on each scale to times under its system
+func computeScales(scales []Scale, systems SystemsMap, cfg PipelineConfig, progress ProgressFunc,
+	times map[string]time.Duration) ([]map[string]interface{}, []error) {
+
+	computed := make([]map[string]interface{}, 0, len(scales))
+	var errs []error
+
+	scaleErrs, durations := computeScalesTimed(scales, systems, cfg.Workers, progress, cfg.ProgressEvery)
+	for i, err := range scaleErrs {
+		times[scales[i].System] += durations[i]
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		computed = append(computed, scales[i].ToOutputMap())
+	}
+
+	return computed, errs
+}
+
+// offsetProgress adapts progress for one batch of a larger run, shifting its
+// counts by offset and reporting the overall total
+func offsetProgress(progress ProgressFunc, offset, total int) ProgressFunc {
+	if progress == nil {
+		return nil
+	}
+	return func(done, _ int) {
+		progress(offset+done, total)
+	}
+}
+
+// ToolVersion is the version recorded in each run's Provenance. Release builds
+// set it with -ldflags "-X erb-power-laws/pkg/rulebook.ToolVersion=<version>".
+var ToolVersion = "dev"
+
+// fitSystems fits each system's actual scales, leaving out systems that cannot be fitted
+func fitSystems(scalesBySystem map[string][]*Scale) map[string]FitResult {
+	fits := make(map[string]FitResult, len(scalesBySystem))
+	for id, scales := range scalesBySystem {
+		fit, err := FitLogLog(scales)
+		if err != nil {
+			continue
+		}
+		fits[id] = fit.Rounded()
+	}
+	return fits
+}
+
+// setTrace attaches trace to every scale with the given ScaleID
+func setTrace(scaleID string, trace TraceFunc, groups ...[]Scale) {
+	for _, group := range groups {
+		for i := range group {
+			if group[i].ScaleID == scaleID {
+				group[i].Trace = trace
+			}
+		}
+	}
+}
+
+// groupScalesBySystem collects scales of known systems from several slices keyed by system ID
+func groupScalesBySystem(systems SystemsMap, groups ...[]Scale) map[string][]*Scale {
+	bySystem := make(map[string][]*Scale)
+	for _, group := range groups {
+		for i := range group {
+			scale := &group[i]
+			if _, ok := systems[scale.System]; !ok {
+				continue
+			}
+			bySystem[scale.System] = append(bySystem[scale.System], scale)
+		}
+	}
+	return bySystem
+}
@@ -0,0 +1,40 @@
+package rulebook
+
+import (
+	"testing"
+)
+
+// Strict mode fails only answer-key scales the pipeline did not compute; the
+// base scales are computed too, so the repo's own data has none missing
+func TestStrictCountsComputedBaseScales(t *testing.T) {
+	cfg := PipelineConfig{
+		BaseDataPath:   testDataPath("base-data.json"),
+		TestInputPaths: []string{testDataPath("test-input.json")},
+		AnswerKeyPath:  testDataPath("answer-key.json"),
+		Strict:         true,
+	}
+	run, err := ExecutePipeline(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missing := make(map[string]bool)
+	for _, f := range run.Failures {
+		if len(f.Mismatches) == 1 && f.Mismatches[0] == "expected in answer key but not computed" {
+			missing[f.ScaleID] = true
+		}
+	}
+	for _, s := range run.BaseData.Scales {
+		if missing[s.ScaleID] {
+			t.Errorf("computed base scale %s reported missing", s.ScaleID)
+		}
+	}
+	for _, s := range run.TestInput.Scales {
+		if missing[s.ScaleID] {
+			t.Errorf("computed test scale %s reported missing", s.ScaleID)
+		}
+	}
+	if run.FailCount != 0 {
+		t.Errorf("strict run on consistent data has %d failures: %v", run.FailCount, run.Failures)
+	}
+}
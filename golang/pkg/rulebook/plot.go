@@ -0,0 +1,626 @@
+//
+// Plot Rendering
+//
+// Log-space plot helpers shared by the terminal and SVG renderers
+//
+
+package rulebook
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+)
+
+// PlotPoint is a single (LogScale, LogMeasure) point taken from an output map
+type PlotPoint struct {
+	X, Y           float64
+	IsProjected    bool
+	IsInterpolated bool
+	ScaleID        string
+	Iteration      float64
+	// HasErrorBar is set for scales aggregated from replicates; YLow and
+	// YHigh are the log of Measure ∓ its stddev (YLow is -Inf when
+	// Measure - stddev is not positive)
+	HasErrorBar bool
+	YLow, YHigh float64
+}
+
+// PlotErrorBars makes the plots draw ±1 stddev error bars on points whose
+// Measure was aggregated from replicates
+var PlotErrorBars = false
+
+// PlotNormalize makes the plots shift each system's points so its anchor (the
+// lowest-iteration actual point) sits at the origin, making slopes directly
+// comparable on shared axes. Only the rendering changes; the scales, fits and
+// validation results are untouched.
+var PlotNormalize = false
+
+// PlotBounds is the log-space extent of a set of plot points
+type PlotBounds struct {
+	XMin, XMax float64
+	YMin, YMax float64
+}
+
+// XRange returns the width of the bounds, or 1 when all points share an x value
+func (b PlotBounds) XRange() float64 {
+	if b.XMax == b.XMin {
+		return 1
+	}
+	return b.XMax - b.XMin
+}
+
+// YRange returns the height of the bounds, or 1 when all points share a y value
+func (b PlotBounds) YRange() float64 {
+	if b.YMax == b.YMin {
+		return 1
+	}
+	return b.YMax - b.YMin
+}
+
+// ExtractPlotPoints collects the plottable points from ToOutputMap-style maps,
+// skipping any scale without numeric LogScale and LogMeasure values and any
+// marked ExtrapolationUnreliable
+func ExtractPlotPoints(scales []map[string]interface{}) []PlotPoint {
+	var points []PlotPoint
+	for _, s := range scales {
+		if unreliable, _ := s["ExtrapolationUnreliable"].(bool); unreliable {
+			continue
+		}
+		logScale, ok1 := s["LogScale"].(float64)
+		logMeasure, ok2 := s["LogMeasure"].(float64)
+		if !ok1 || !ok2 {
+			continue
+		}
+		isProj, _ := s["IsProjected"].(bool)
+		isInterp, _ := s["IsInterpolated"].(bool)
+		scaleID, _ := s["ScaleID"].(string)
+		p := PlotPoint{
+			X:              logScale,
+			Y:              logMeasure,
+			IsProjected:    isProj,
+			IsInterpolated: isInterp,
+			ScaleID:        scaleID,
+			Iteration:      OutputIteration(s),
+		}
+		measure, ok1 := toFloat64(s["Measure"])
+		stddev, ok2 := toFloat64(s["MeasureStddev"])
+		if ok1 && ok2 && stddev > 0 {
+			base := outputLogBase(s)
+			p.HasErrorBar = true
+			p.YHigh = logBase(measure+stddev, base)
+			p.YLow = math.Inf(-1)
+			if measure > stddev {
+				p.YLow = logBase(measure-stddev, base)
+			}
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// plotAnchor returns the log-space position of the actual point with the
+// lowest iteration, falling back to the first point when every point is
+// projected or interpolated
+func plotAnchor(points []PlotPoint) (x, y float64) {
+	anchor := -1
+	for i, p := range points {
+		if p.IsProjected || p.IsInterpolated {
+			continue
+		}
+		if anchor < 0 || p.Iteration < points[anchor].Iteration {
+			anchor = i
+		}
+	}
+	if anchor < 0 {
+		anchor = 0
+	}
+	return points[anchor].X, points[anchor].Y
+}
+
+// NormalizePlotPoints returns copies of points and fit shifted so the system's
+// anchor sits at the origin when PlotNormalize is set, and the inputs
+// unchanged otherwise. The fit is moved along with the points, so its slope
+// and band still line up with them.
+func NormalizePlotPoints(points []PlotPoint, fit *FitResult) ([]PlotPoint, *FitResult) {
+	if !PlotNormalize || len(points) == 0 {
+		return points, fit
+	}
+	dx, dy := plotAnchor(points)
+	shifted := make([]PlotPoint, len(points))
+	for i, p := range points {
+		p.X -= dx
+		p.Y -= dy
+		p.YLow -= dy
+		p.YHigh -= dy
+		shifted[i] = p
+	}
+	if fit != nil {
+		f := fit.Shifted(dx, dy)
+		fit = &f
+	}
+	return shifted, fit
+}
+
+// PlotAxisLabel labels a log-log plot axis for quantity ("Scale" or
+// "Measure"), as a ratio to the anchor's value when PlotNormalize is set
+func PlotAxisLabel(logLabel, quantity string) string {
+	if PlotNormalize {
+		return logLabel + "(" + quantity + "/" + quantity + "₀)"
+	}
+	return logLabel + "(" + quantity + ")"
+}
+
+// ComputePlotBounds returns the min/max extent of the points in log space
+func ComputePlotBounds(points []PlotPoint) PlotBounds {
+	if len(points) == 0 {
+		return PlotBounds{}
+	}
+	b := PlotBounds{XMin: points[0].X, XMax: points[0].X, YMin: points[0].Y, YMax: points[0].Y}
+	for _, p := range points {
+		if p.X < b.XMin {
+			b.XMin = p.X
+		}
+		if p.X > b.XMax {
+			b.XMax = p.X
+		}
+		if p.Y < b.YMin {
+			b.YMin = p.Y
+		}
+		if p.Y > b.YMax {
+			b.YMax = p.Y
+		}
+	}
+	return b
+}
+
+// SVG plot styling
+const (
+	svgMarginLeft   = 70
+	svgMarginRight  = 20
+	svgMarginTop    = 40
+	svgMarginBottom = 60
+	svgActualColor  = "#2ca02c"
+	svgProjColor    = "#c000c0"
+	svgInterpColor  = "#1f77b4"
+	svgTheoryColor  = "#888888"
+	svgBandColor    = "#ff7f0e"
+	svgMarkerRadius = 4
+)
+
+// RenderSVGPlot renders a standalone SVG log-log plot of a system's scales with
+// axes, the theoretical slope line through the first point, and distinct markers
+// for actual (filled) and projected (hollow) points. When fit is non-nil and has
+// a standard error, a shaded ±1 SE band around the fitted slope is drawn too.
+func RenderSVGPlot(scales []map[string]interface{}, system *System, fit *FitResult, width, height int) string {
+	points, fit := NormalizePlotPoints(ExtractPlotPoints(scales), fit)
+	bounds := ComputePlotBounds(points)
+
+	plotW := float64(width - svgMarginLeft - svgMarginRight)
+	plotH := float64(height - svgMarginTop - svgMarginBottom)
+	toSVG := func(x, y float64) (float64, float64) {
+		sx := float64(svgMarginLeft) + (x-bounds.XMin)/bounds.XRange()*plotW
+		sy := float64(svgMarginTop) + plotH - (y-bounds.YMin)/bounds.YRange()*plotH
+		return sx, sy
+	}
+
+	logLabel := system.LogLabel()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `  <title>%s</title>`+"\n", html.EscapeString(system.DisplayName))
+	fmt.Fprintf(&b, `  <rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `  <text x="%d" y="%d" font-size="14" font-weight="bold">%s</text>`+"\n",
+		svgMarginLeft, svgMarginTop/2+5, html.EscapeString(system.DisplayName))
+
+	if len(points) == 0 {
+		fmt.Fprintf(&b, `  <text x="%d" y="%d">No valid data points</text>`+"\n", width/2-60, height/2)
+		b.WriteString("</svg>\n")
+		return b.String()
+	}
+
+	// Clip the theoretical line to the plot area
+	fmt.Fprintf(&b, `  <defs><clipPath id="plot-area"><rect x="%d" y="%d" width="%.1f" height="%.1f"/></clipPath></defs>`+"\n",
+		svgMarginLeft, svgMarginTop, plotW, plotH)
+
+	writeSVGAxes(&b, bounds, plotW, plotH, system.WithScaleUnit(PlotAxisLabel(logLabel, "Scale")), system.WithMeasureUnit(PlotAxisLabel(logLabel, "Measure")))
+
+	// ±1 SE band around the fitted slope, pivoting at the centroid
+	drawBand := fit != nil && fit.HasStdErr()
+	if drawBand {
+		cx, cy := toSVG(fit.MeanLogScale, fit.Predict(fit.MeanLogScale))
+		loMin, hiMin := fit.SlopeBand(bounds.XMin)
+		loMax, hiMax := fit.SlopeBand(bounds.XMax)
+		ax, ay := toSVG(bounds.XMin, hiMin)
+		bx, by := toSVG(bounds.XMax, hiMax)
+		dx, dy := toSVG(bounds.XMax, loMax)
+		ex, ey := toSVG(bounds.XMin, loMin)
+		fmt.Fprintf(&b, `  <polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s" fill-opacity="0.2" clip-path="url(#plot-area)"><title>Fitted slope %.3f ± %.3f (1 SE)</title></polygon>`+"\n",
+			ax, ay, cx, cy, bx, by, dx, dy, cx, cy, ex, ey, svgBandColor, fit.Slope, fit.SlopeStdErr)
+	}
+
+	// Theoretical slope line through the first point
+	slope := system.TheoreticalLogLogSlope
+	if slope != 0 {
+		px, py := points[0].X, points[0].Y
+		lx1, ly1 := toSVG(bounds.XMin, py+slope*(bounds.XMin-px))
+		lx2, ly2 := toSVG(bounds.XMax, py+slope*(bounds.XMax-px))
+		fmt.Fprintf(&b, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="4 3" clip-path="url(#plot-area)"><title>Theoretical slope %.3f</title></line>`+"\n",
+			lx1, ly1, lx2, ly2, svgTheoryColor, slope)
+	}
+
+	// Replicate error bars, clipped to the plot area
+	if PlotErrorBars {
+		for _, p := range points {
+			if !p.HasErrorBar {
+				continue
+			}
+			x, yHigh := toSVG(p.X, p.YHigh)
+			_, yLow := toSVG(p.X, math.Max(p.YLow, bounds.YMin))
+			fmt.Fprintf(&b, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" clip-path="url(#plot-area)"><title>%s ±1σ of replicates</title></line>`+"\n",
+				x, yLow, x, yHigh, svgTheoryColor, html.EscapeString(p.ScaleID))
+		}
+	}
+
+	// Points
+	for _, p := range points {
+		cx, cy := toSVG(p.X, p.Y)
+		if p.IsInterpolated {
+			r := float64(svgMarkerRadius) + 1
+			fmt.Fprintf(&b, `  <polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s"><title>%s (iteration %g, interpolated)</title></polygon>`+"\n",
+				cx, cy-r, cx+r, cy, cx, cy+r, cx-r, cy, svgInterpColor, html.EscapeString(p.ScaleID), p.Iteration)
+			continue
+		}
+		kind, fill := "actual", svgActualColor
+		if p.IsProjected {
+			kind, fill = "projected", "white"
+		}
+		stroke := svgActualColor
+		if p.IsProjected {
+			stroke = svgProjColor
+		}
+		fmt.Fprintf(&b, `  <circle cx="%.1f" cy="%.1f" r="%d" fill="%s" stroke="%s" stroke-width="1.5"><title>%s (iteration %g, %s)</title></circle>`+"\n",
+			cx, cy, svgMarkerRadius, fill, stroke, html.EscapeString(p.ScaleID), p.Iteration, kind)
+	}
+
+	// Legend
+	ly := float64(height) - 12
+	fmt.Fprintf(&b, `  <circle cx="%d" cy="%.1f" r="%d" fill="%s"/><text x="%d" y="%.1f">Actual</text>`+"\n",
+		svgMarginLeft, ly-4, svgMarkerRadius, svgActualColor, svgMarginLeft+8, ly)
+	fmt.Fprintf(&b, `  <circle cx="%d" cy="%.1f" r="%d" fill="white" stroke="%s" stroke-width="1.5"/><text x="%d" y="%.1f">Projected</text>`+"\n",
+		svgMarginLeft+70, ly-4, svgMarkerRadius, svgProjColor, svgMarginLeft+78, ly)
+	fmt.Fprintf(&b, `  <line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s" stroke-dasharray="4 3"/><text x="%d" y="%.1f">Theoretical (slope=%.3f)</text>`+"\n",
+		svgMarginLeft+160, ly-4, svgMarginLeft+180, ly-4, svgTheoryColor, svgMarginLeft+186, ly, slope)
+	if drawBand {
+		fmt.Fprintf(&b, `  <rect x="%d" y="%.1f" width="20" height="8" fill="%s" fill-opacity="0.2"/><text x="%d" y="%.1f">±1 SE fit</text>`+"\n",
+			svgMarginLeft+345, ly-8, svgBandColor, svgMarginLeft+371, ly)
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// writeSVGAxes draws the x and y axes with their extent and labels
+func writeSVGAxes(b *strings.Builder, bounds PlotBounds, plotW, plotH float64, xLabel, yLabel string) {
+	x0, y0 := float64(svgMarginLeft), float64(svgMarginTop)+plotH
+	fmt.Fprintf(b, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", x0, y0, x0+plotW, y0)
+	fmt.Fprintf(b, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", x0, y0, x0, float64(svgMarginTop))
+	fmt.Fprintf(b, `  <text x="%.1f" y="%.1f" text-anchor="start">%.2f</text>`+"\n", x0, y0+16, bounds.XMin)
+	fmt.Fprintf(b, `  <text x="%.1f" y="%.1f" text-anchor="end">%.2f</text>`+"\n", x0+plotW, y0+16, bounds.XMax)
+	fmt.Fprintf(b, `  <text x="%.1f" y="%.1f" text-anchor="end">%.2f</text>`+"\n", x0-6, y0, bounds.YMin)
+	fmt.Fprintf(b, `  <text x="%.1f" y="%.1f" text-anchor="end">%.2f</text>`+"\n", x0-6, float64(svgMarginTop)+10, bounds.YMax)
+	fmt.Fprintf(b, `  <text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x0+plotW/2, y0+36, html.EscapeString(xLabel))
+	fmt.Fprintf(b, `  <text x="%d" y="%.1f" text-anchor="middle" transform="rotate(-90 %d %.1f)">%s</text>`+"\n",
+		18, float64(svgMarginTop)+plotH/2, 18, float64(svgMarginTop)+plotH/2, html.EscapeString(yLabel))
+}
+
+// Overlay series styling, cycled in system ID order
+var (
+	overlayColors  = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+	overlayMarkers = []string{"circle", "square", "triangle", "diamond"}
+)
+
+// writeSVGMarker draws one marker shape centered on (cx, cy). Projected points
+// are drawn hollow.
+func writeSVGMarker(b *strings.Builder, shape string, cx, cy float64, color string, hollow bool, title string) {
+	fill := color
+	if hollow {
+		fill = "white"
+	}
+	r := float64(svgMarkerRadius)
+	style := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="1.5"`, fill, color)
+	switch shape {
+	case "square":
+		fmt.Fprintf(b, `  <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" %s>`, cx-r, cy-r, 2*r, 2*r, style)
+		fmt.Fprintf(b, "%s</rect>\n", title)
+	case "triangle":
+		fmt.Fprintf(b, `  <polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" %s>`, cx, cy-r-1, cx+r+1, cy+r, cx-r-1, cy+r, style)
+		fmt.Fprintf(b, "%s</polygon>\n", title)
+	case "diamond":
+		fmt.Fprintf(b, `  <polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" %s>`, cx, cy-r-1, cx+r+1, cy, cx, cy+r+1, cx-r-1, cy, style)
+		fmt.Fprintf(b, "%s</polygon>\n", title)
+	default:
+		fmt.Fprintf(b, `  <circle cx="%.1f" cy="%.1f" r="%.1f" %s>`, cx, cy, r, style)
+		fmt.Fprintf(b, "%s</circle>\n", title)
+	}
+}
+
+// RenderOverlayPlot renders the scales of several systems on one SVG log-log
+// chart with shared bounds, giving each system its own color and marker shape
+// and a legend mapping them to system names. The axes are labelled with the
+// shared log base, or plain "log" when the systems use different bases.
+func RenderOverlayPlot(scalesBySystem map[string][]map[string]interface{}, systems SystemsMap, width, height int) string {
+	systemIDs := make([]string, 0, len(scalesBySystem))
+	for id := range scalesBySystem {
+		systemIDs = append(systemIDs, id)
+	}
+	sort.Strings(systemIDs)
+
+	pointsBySystem := make(map[string][]PlotPoint, len(systemIDs))
+	var allPoints []PlotPoint
+	logLabel := ""
+	for _, id := range systemIDs {
+		points, _ := NormalizePlotPoints(ExtractPlotPoints(scalesBySystem[id]), nil)
+		pointsBySystem[id] = points
+		allPoints = append(allPoints, points...)
+
+		label := "log"
+		if system, ok := systems[id]; ok {
+			label = system.LogLabel()
+		}
+		if logLabel == "" {
+			logLabel = label
+		} else if logLabel != label {
+			logLabel = "log"
+		}
+	}
+	bounds := ComputePlotBounds(allPoints)
+
+	plotW := float64(width - svgMarginLeft - svgMarginRight)
+	plotH := float64(height - svgMarginTop - svgMarginBottom)
+	toSVG := func(x, y float64) (float64, float64) {
+		sx := float64(svgMarginLeft) + (x-bounds.XMin)/bounds.XRange()*plotW
+		sy := float64(svgMarginTop) + plotH - (y-bounds.YMin)/bounds.YRange()*plotH
+		return sx, sy
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	b.WriteString("  <title>System overlay</title>\n")
+	fmt.Fprintf(&b, `  <rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `  <text x="%d" y="%d" font-size="14" font-weight="bold">System overlay</text>`+"\n",
+		svgMarginLeft, svgMarginTop/2+5)
+
+	if len(allPoints) == 0 {
+		fmt.Fprintf(&b, `  <text x="%d" y="%d">No valid data points</text>`+"\n", width/2-60, height/2)
+		b.WriteString("</svg>\n")
+		return b.String()
+	}
+
+	writeSVGAxes(&b, bounds, plotW, plotH, PlotAxisLabel(logLabel, "Scale"), PlotAxisLabel(logLabel, "Measure"))
+
+	for i, id := range systemIDs {
+		color := overlayColors[i%len(overlayColors)]
+		shape := overlayMarkers[i%len(overlayMarkers)]
+		for _, p := range pointsBySystem[id] {
+			cx, cy := toSVG(p.X, p.Y)
+			title := fmt.Sprintf("<title>%s (iteration %g)</title>", html.EscapeString(p.ScaleID), p.Iteration)
+			writeSVGMarker(&b, shape, cx, cy, color, p.IsProjected, title)
+		}
+	}
+
+	// Legend, top-right inside the plot area
+	lx := float64(width-svgMarginRight) - 170
+	for i, id := range systemIDs {
+		name := id
+		if system, ok := systems[id]; ok && system.DisplayName != "" {
+			name = system.DisplayName
+		}
+		ly := float64(svgMarginTop) + 12 + float64(i)*16
+		writeSVGMarker(&b, overlayMarkers[i%len(overlayMarkers)], lx, ly-4, overlayColors[i%len(overlayColors)], false, "")
+		fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f">%s</text>`+"\n", lx+10, ly, html.EscapeString(name))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// RenderResidualPlot renders a plain-text plot of each point's residual from
+// the theoretical slope line through the actual points, against iteration,
+// with a zero baseline. Systematic curvature away from zero means the power
+// law does not hold over the plotted range.
+func RenderResidualPlot(scales []map[string]interface{}, system *System, width, height int) string {
+	points := ExtractPlotPoints(scales)
+
+	// Intercept of the theoretical line that best fits the actual points
+	slope := system.TheoreticalLogLogSlope
+	var sum float64
+	actuals := 0
+	for _, p := range points {
+		if !p.IsProjected && !p.IsInterpolated {
+			sum += p.Y - slope*p.X
+			actuals++
+		}
+	}
+	if actuals == 0 {
+		return "  (No actual data points)"
+	}
+	intercept := sum / float64(actuals)
+
+	// Symmetric y range around the zero baseline
+	residuals := make([]float64, len(points))
+	maxAbs := 0.0
+	iterMin, iterMax := points[0].Iteration, points[0].Iteration
+	for i, p := range points {
+		residuals[i] = p.Y - (intercept + slope*p.X)
+		maxAbs = math.Max(maxAbs, math.Abs(residuals[i]))
+		iterMin = math.Min(iterMin, p.Iteration)
+		iterMax = math.Max(iterMax, p.Iteration)
+	}
+	if maxAbs == 0 {
+		maxAbs = 1
+	}
+	iterRange := iterMax - iterMin
+	if iterRange == 0 {
+		iterRange = 1
+	}
+
+	grid := make([][]string, height)
+	for i := range grid {
+		grid[i] = make([]string, width)
+		for j := range grid[i] {
+			grid[i][j] = " "
+		}
+	}
+	toRow := func(r float64) int {
+		return int(math.Round((maxAbs - r) / (2 * maxAbs) * float64(height-1)))
+	}
+
+	zeroRow := toRow(0)
+	for j := range grid[zeroRow] {
+		grid[zeroRow][j] = "-"
+	}
+	for i, p := range points {
+		col := int(math.Round((p.Iteration - iterMin) / iterRange * float64(width-1)))
+		marker := "●"
+		switch {
+		case p.IsProjected:
+			marker = "◌"
+		case p.IsInterpolated:
+			marker = "◇"
+		}
+		grid[toRow(residuals[i])][col] = marker
+	}
+
+	var lines []string
+	lines = append(lines, "  residual (log units)")
+	for i, row := range grid {
+		label := "         "
+		switch i {
+		case 0:
+			label = fmt.Sprintf(" %+8.4f", maxAbs)
+		case zeroRow:
+			label = fmt.Sprintf(" %8.4f", 0.0)
+		case height - 1:
+			label = fmt.Sprintf(" %+8.4f", -maxAbs)
+		}
+		lines = append(lines, label+" ┤"+strings.Join(row, ""))
+	}
+	lines = append(lines, "          └"+strings.Repeat("─", width))
+	padding := width - 8
+	if padding < 1 {
+		padding = 1
+	}
+	lines = append(lines, fmt.Sprintf("          %-4g%s%4g", iterMin, strings.Repeat(" ", padding), iterMax))
+	lines = append(lines, "          "+strings.Repeat(" ", width/2-4)+"Iteration")
+	return strings.Join(lines, "\n")
+}
+
+// PlotColor lets terminal renderings such as RenderResidualHeatmap use ANSI
+// colors; when false they fall back to shading characters
+var PlotColor = true
+
+// Heatmap levels, lowest residual first: ANSI background colors when
+// PlotColor is set, shading characters otherwise
+var (
+	heatmapColors = []string{"\033[42m", "\033[46m", "\033[43m", "\033[41m"}
+	heatmapShades = []string{"░", "▒", "▓", "█"}
+)
+
+// RenderResidualHeatmap renders a grid of |residual| from each system's
+// theoretical line through its actual points, one row per system and one
+// column per iteration, projected scales included. Cells are binned into
+// equal bands of log10 |residual| between the smallest and largest nonzero
+// residual, so a single poorly fitting point does not wash out the rest.
+// Iterations a system has no scale for are shown as "·".
+func RenderResidualHeatmap(scalesBySystem map[string][]*Scale, systems SystemsMap) string {
+	systemIDs := make([]string, 0, len(scalesBySystem))
+	cells := make(map[string]map[int]float64)
+	iterSet := make(map[int]bool)
+	minLog, maxLog := math.Inf(1), math.Inf(-1)
+	for systemID, scales := range scalesBySystem {
+		system, ok := systems[systemID]
+		if !ok {
+			continue
+		}
+		slope := system.TheoreticalLogLogSlope
+		intercept, ok := theoreticalIntercept(scales, slope)
+		if !ok {
+			continue
+		}
+		systemIDs = append(systemIDs, systemID)
+		cells[systemID] = make(map[int]float64)
+		for _, s := range scales {
+			if !s.LogScaleValid() || !s.LogMeasureValid() {
+				continue
+			}
+			abs := math.Abs(s.GetLogMeasure() - (intercept + slope*s.GetLogScale()))
+			cells[systemID][s.Iteration] = abs
+			iterSet[s.Iteration] = true
+			if abs > 0 {
+				minLog = math.Min(minLog, math.Log10(abs))
+				maxLog = math.Max(maxLog, math.Log10(abs))
+			}
+		}
+	}
+	if len(systemIDs) == 0 {
+		return "  (No actual data points)"
+	}
+	sort.Strings(systemIDs)
+	iterations := make([]int, 0, len(iterSet))
+	for it := range iterSet {
+		iterations = append(iterations, it)
+	}
+	sort.Ints(iterations)
+
+	levels := len(heatmapShades)
+	if math.IsInf(minLog, 1) {
+		minLog, maxLog = 0, 0
+	}
+	band := (maxLog - minLog) / float64(levels)
+	level := func(abs float64) int {
+		if abs == 0 || band == 0 {
+			return 0
+		}
+		return min(int((math.Log10(abs)-minLog)/band), levels-1)
+	}
+	cell := func(l int) string {
+		if PlotColor {
+			return heatmapColors[l] + "   " + "\033[0m"
+		}
+		return strings.Repeat(heatmapShades[l], 3)
+	}
+
+	var lines []string
+	header := fmt.Sprintf("  %-14s", "Iteration")
+	for _, it := range iterations {
+		header += fmt.Sprintf(" %3d", it)
+	}
+	lines = append(lines, header)
+	for _, systemID := range systemIDs {
+		row := fmt.Sprintf("  %-14s", systemID)
+		for _, it := range iterations {
+			abs, ok := cells[systemID][it]
+			if !ok {
+				row += "  · "
+				continue
+			}
+			row += " " + cell(level(abs))
+		}
+		lines = append(lines, row)
+	}
+
+	legend := "  |r| (log units):"
+	for l := 0; l < levels; l++ {
+		if band == 0 && l > 0 {
+			break
+		}
+		upper := math.Pow(10, minLog+band*float64(l+1))
+		legend += fmt.Sprintf("  %s ≤ %.1e", cell(l), upper)
+	}
+	lines = append(lines, "", legend)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,193 @@
+//
+// Report Writers
+//
+// Machine-readable validation reports for CI systems
+//
+
+package rulebook
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"sort"
+)
+
+// junitTestSuite is the root <testsuite> element of a JUnit XML report
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is one scale's <testcase>, with a <failure> per mismatch when
+// it did not validate
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Detail  string `xml:",chardata"`
+}
+
+// SaveJUnitReport writes a JUnit XML report with one <testcase> per scale ID.
+// Each mismatch of a failed scale becomes its own <failure>, with the
+// expected and got values in its message; failures for IDs not in scaleIDs
+// (e.g. missing from the computed set) are appended. It takes the validated
+// scale IDs rather than pass and fail counts so that passing scales appear as
+// named test cases too. XML-special characters in IDs and messages are
+// escaped by encoding/xml.
+func SaveJUnitReport(path string, scaleIDs []string, failures []ValidationResult) error {
+	failureByID := make(map[string]ValidationResult, len(failures))
+	for _, f := range failures {
+		failureByID[f.ScaleID] = f
+	}
+
+	suite := junitTestSuite{Name: "power-laws-and-fractals"}
+	listed := make(map[string]bool, len(scaleIDs))
+	addCase := func(id string) {
+		tc := junitTestCase{Name: id, ClassName: "rulebook.ValidateScale"}
+		if f, failed := failureByID[id]; failed {
+			for _, mismatch := range f.Mismatches {
+				tc.Failures = append(tc.Failures, junitFailure{Message: mismatch, Detail: f.Cause})
+			}
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+		listed[id] = true
+	}
+
+	for _, id := range scaleIDs {
+		addCase(id)
+	}
+	for _, f := range failures {
+		if !listed[f.ScaleID] {
+			addCase(f.ScaleID)
+		}
+	}
+	suite.Tests = len(suite.TestCases)
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0644)
+}
+
+// RunSummary is the rollup of a run for dashboards, written by
+// SaveRunSummary: overall and per-system pass/fail counts, fit quality and
+// warning counts, without the per-scale values of the results file
+type RunSummary struct {
+	Platform  string `json:"platform"`
+	Timestamp string `json:"timestamp"`
+	// Passed and Failed count validated scales; PassRate is 1 when none were
+	Passed        int     `json:"passed"`
+	Failed        int     `json:"failed"`
+	PassRate      float64 `json:"passRate"`
+	StoppedEarly  bool    `json:"stoppedEarly,omitempty"`
+	ComputeErrors int     `json:"computeErrors"`
+	SlopeFailures int     `json:"slopeFailures"`
+	// Warnings is the number of report warnings; the rulebook package does
+	// not produce warnings itself, so callers fill it in
+	Warnings int             `json:"warnings"`
+	Systems  []SystemSummary `json:"systems"`
+}
+
+// SystemSummary is one system's entry in a RunSummary. FittedSlope and
+// RSquared are nil when the system's actual scales could not be fitted.
+type SystemSummary struct {
+	SystemID         string   `json:"systemId"`
+	TheoreticalSlope float64  `json:"theoreticalSlope"`
+	FittedSlope      *float64 `json:"fittedSlope"`
+	RSquared         *float64 `json:"rSquared"`
+	SlopePassed      bool     `json:"slopePassed"`
+	Passed           int      `json:"passed"`
+	Failed           int      `json:"failed"`
+	Warnings         int      `json:"warnings"`
+}
+
+// BuildRunSummary rolls a pipeline run and its slope validation up into a
+// RunSummary, with systems sorted by ID. Per-system counts cover the
+// validated test scales; strict-mode failures for scales that were never
+// computed only appear in the overall Failed count.
+func BuildRunSummary(run *PipelineRun, slopeResults []ValidationResult) RunSummary {
+	summary := RunSummary{
+		Platform:      run.Results.Platform,
+		Timestamp:     run.Results.Timestamp,
+		Passed:        run.PassCount,
+		Failed:        run.FailCount,
+		PassRate:      1,
+		StoppedEarly:  run.StoppedEarly,
+		ComputeErrors: len(run.ComputeErrors),
+	}
+	if total := run.PassCount + run.FailCount; total > 0 {
+		summary.PassRate = OutputRounding.Apply(float64(run.PassCount) / float64(total))
+	}
+
+	failed := make(map[string]bool, len(run.Failures))
+	for _, f := range run.Failures {
+		failed[f.ScaleID] = true
+	}
+	// Validation runs over the results in order, so an early stop leaves
+	// only a prefix validated
+	validated := run.Results.Scales
+	if run.StoppedEarly {
+		validated = validated[:min(len(validated), run.PassCount+run.FailCount)]
+	}
+	counts := make(map[string]*SystemSummary)
+	for _, scale := range validated {
+		systemID, _ := scale["System"].(string)
+		if counts[systemID] == nil {
+			counts[systemID] = &SystemSummary{}
+		}
+		if scaleID, _ := scale["ScaleID"].(string); failed[scaleID] {
+			counts[systemID].Failed++
+		} else {
+			counts[systemID].Passed++
+		}
+	}
+	slopePassed := make(map[string]bool, len(slopeResults))
+	for _, r := range slopeResults {
+		slopePassed[r.ScaleID] = r.Passed
+		if !r.Passed {
+			summary.SlopeFailures++
+		}
+	}
+
+	systemIDs := make([]string, 0, len(run.ScalesBySystem))
+	for id := range run.ScalesBySystem {
+		systemIDs = append(systemIDs, id)
+	}
+	sort.Strings(systemIDs)
+	for _, id := range systemIDs {
+		entry := SystemSummary{SystemID: id, SlopePassed: slopePassed[id]}
+		if c := counts[id]; c != nil {
+			entry.Passed, entry.Failed = c.Passed, c.Failed
+		}
+		if system, ok := run.Systems[id]; ok {
+			entry.TheoreticalSlope = system.TheoreticalLogLogSlope
+		}
+		if fit, ok := run.Results.Fits[id]; ok {
+			entry.FittedSlope, entry.RSquared = &fit.Slope, &fit.RSquared
+		}
+		summary.Systems = append(summary.Systems, entry)
+	}
+	return summary
+}
+
+// SaveRunSummary writes the summary as indented JSON
+func SaveRunSummary(path string, summary RunSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0644)
+}
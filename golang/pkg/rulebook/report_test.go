@@ -0,0 +1,43 @@
+package rulebook
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveJUnitReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	failures := []ValidationResult{
+		{ScaleID: `Koch<&>"2"`, Mismatches: []string{"Scale: expected 1, got 2", "LogScale: expected 0, got 0.3"}},
+		{ScaleID: "Koch_9", Mismatches: []string{"expected in answer key but not computed"}},
+	}
+	if err := SaveJUnitReport(path, []string{"Koch_0", `Koch<&>"2"`}, failures); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suite junitTestSuite
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("report is not valid XML: %v\n%s", err, data)
+	}
+	if suite.Tests != 3 || suite.Failures != 2 {
+		t.Errorf("tests=%d failures=%d, want 3 and 2", suite.Tests, suite.Failures)
+	}
+	for i, want := range []struct {
+		name     string
+		failures int
+	}{{"Koch_0", 0}, {`Koch<&>"2"`, 2}, {"Koch_9", 1}} {
+		tc := suite.TestCases[i]
+		if tc.Name != want.name || len(tc.Failures) != want.failures {
+			t.Errorf("testcase %d: %q with %d failures, want %q with %d", i, tc.Name, len(tc.Failures), want.name, want.failures)
+		}
+	}
+	if got := suite.TestCases[1].Failures[1].Message; got != "LogScale: expected 0, got 0.3" {
+		t.Errorf("second failure message %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+//
+// Streaming Answer Key
+//
+// Reads large answer keys element by element with json.Decoder, building the
+// ScaleID lookup used for validation without holding the whole file or the
+// full scales slice in memory
+//
+
+package rulebook
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// AnswerKeyIndex is the answer key's expected scales keyed by ScaleID, with
+// the IDs kept in file order for deterministic reporting
+type AnswerKeyIndex struct {
+	Scales map[string]map[string]interface{}
+	IDs    []string
+
+	// SHA256 is the hash of the answer key's content, as in AnswerKey.SHA256
+	SHA256 string
+}
+
+// add indexes one expected scale. A repeated ScaleID replaces the earlier
+// scale but keeps its position; it reports whether the ID was already present.
+func (idx *AnswerKeyIndex) add(scale map[string]interface{}) bool {
+	scaleID, ok := scale["ScaleID"].(string)
+	if !ok {
+		return false
+	}
+	_, dup := idx.Scales[scaleID]
+	if !dup {
+		idx.IDs = append(idx.IDs, scaleID)
+	}
+	idx.Scales[scaleID] = scale
+	return dup
+}
+
+// IndexAnswerKey builds the lookup for an answer key already loaded in memory
+func IndexAnswerKey(answerKey *AnswerKey) *AnswerKeyIndex {
+	idx := &AnswerKeyIndex{
+		Scales: make(map[string]map[string]interface{}, len(answerKey.Scales)),
+		SHA256: answerKey.SHA256,
+	}
+	for _, s := range answerKey.Scales {
+		idx.add(s)
+	}
+	return idx
+}
+
+// StreamAnswerKey reads an answer-key JSON file or URL (optionally gzipped) one scale
+// at a time, indexing only the scales selected by filter. Duplicate ScaleIDs
+// are handled as in LoadAnswerKey. YAML answer keys cannot be streamed.
+func StreamAnswerKey(path string, filter ScaleFilter) (*AnswerKeyIndex, error) {
+	if isYAMLPath(path) {
+		return nil, fmt.Errorf("%s: streaming is only supported for JSON answer keys", path)
+	}
+
+	f, err := openData(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := maybeGzipReader(path, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hash everything read, draining what the decoder leaves behind
+	h := sha256.New()
+	r = io.TeeReader(r, h)
+	idx, err := decodeAnswerKeyStream(json.NewDecoder(r), filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	idx.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return idx, nil
+}
+
+// maybeGzipReader wraps r in a gzip reader when the path has a .gz extension
+// or the stream starts with the gzip magic bytes
+func maybeGzipReader(path string, r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(gzipMagic))
+	if !strings.HasSuffix(dataFileName(path), ".gz") && !bytes.Equal(magic, gzipMagic) {
+		return br, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid gzip stream: %w", path, err)
+	}
+	return zr, nil
+}
+
+// decodeAnswerKeyStream walks the top-level answer-key object, decoding the
+// "scales" array element by element and skipping every other field
+func decodeAnswerKeyStream(dec *json.Decoder, filter ScaleFilter) (*AnswerKeyIndex, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	idx := &AnswerKeyIndex{Scales: make(map[string]map[string]interface{})}
+	var collisions []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key, _ := tok.(string); key != "scales" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, err
+		}
+		for dec.More() {
+			var scale map[string]interface{}
+			if err := dec.Decode(&scale); err != nil {
+				return nil, err
+			}
+			system, _ := scale["System"].(string)
+			if !filter.Matches(system, OutputIteration(scale)) {
+				continue
+			}
+			if idx.add(scale) && !slices.Contains(collisions, scale["ScaleID"].(string)) {
+				collisions = append(collisions, scale["ScaleID"].(string))
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(collisions) > 0 && !KeepLastDuplicates {
+		return nil, fmt.Errorf("duplicate ScaleIDs: %s", strings.Join(collisions, ", "))
+	}
+	return idx, nil
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
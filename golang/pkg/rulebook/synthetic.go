@@ -0,0 +1,69 @@
+//
+// Synthetic Data
+//
+// Generates test inputs along a system's theoretical power law, with optional
+// noise, for stress-testing the fitting and validation code
+//
+
+package rulebook
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+)
+
+// GenerateSyntheticScales returns scales for iterations 0 through
+// iterations-1 whose Measures lie on the system's theoretical log-log line
+// through Measure 1 at Scale 1, i.e. LogMeasure = slope * LogScale. When
+// noiseStddev is positive, Gaussian noise with that standard deviation is
+// added to each LogMeasure (multiplicative noise on Measure). ScaleIDs follow
+// GeneratedScaleID. The same seed always gives the same scales.
+func GenerateSyntheticScales(system *System, iterations int, noiseStddev float64, seed int64) []Scale {
+	rng := rand.New(rand.NewSource(seed))
+	base := system.EffectiveLogBase()
+
+	scales := make([]Scale, 0, max(iterations, 0))
+	for iter := 0; iter < iterations; iter++ {
+		scaleValue := system.BaseScale * system.ScaleFactorPower(float64(iter))
+		logMeasure := system.TheoreticalLogLogSlope * logBase(scaleValue, base)
+		if noiseStddev > 0 {
+			logMeasure += rng.NormFloat64() * noiseStddev
+		}
+
+		s := Scale{
+			System:    system.SystemID,
+			Iteration: iter,
+			Measure:   floatPtr(math.Pow(base, logMeasure)),
+		}
+		s.ScaleID = GeneratedScaleID(&s)
+		scales = append(scales, s)
+	}
+	return scales
+}
+
+// SaveTestInput writes a test input as indented JSON
+func SaveTestInput(path string, input *TestInput) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := WriteTestInput(f, input); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WriteTestInput writes a test input to w as indented JSON, as SaveTestInput does
+func WriteTestInput(w io.Writer, input *TestInput) error {
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -9,21 +9,132 @@ package rulebook
 import (
 	"fmt"
 	"math"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-// Tolerance for floating point comparisons (allows for floating-point precision in 6dp comparisons)
+// DefaultTolerance for floating point comparisons (allows for floating-point precision in 6dp comparisons)
 // Using 0.0000015 to handle rounding at the 6th decimal place boundary
-const Tolerance = 0.0000015
+const DefaultTolerance = 0.0000015
+
+// Tolerance is the absolute tolerance used by CompareValues and as the default
+// per-field tolerance. The answer key was generated at DefaultTolerance, so
+// loosening it can mask real errors. Change it with SetTolerance.
+var Tolerance = DefaultTolerance
+
+// SetTolerance sets Tolerance, rejecting values that are not positive and finite
+func SetTolerance(tol float64) error {
+	if !(tol > 0) || math.IsInf(tol, 0) {
+		return fmt.Errorf("tolerance must be a positive number, got %g", tol)
+	}
+	Tolerance = tol
+	return nil
+}
+
+// RelTolerance is the relative tolerance applied to fields that span many orders of magnitude
+const RelTolerance = 1e-9
+
+// relativeFields are compared with RelTolerance in addition to their absolute tolerance,
+// since large Scale values can exceed any fixed absolute tolerance through rounding alone
+var relativeFields = map[string]bool{
+	"ScaleFactorPower": true,
+	"Scale":            true,
+}
+
+// logFields are computed as logarithms and are nil when their input is non-positive
+var logFields = map[string]bool{
+	"LogScale":   true,
+	"LogMeasure": true,
+}
+
+// CompareStrategy selects how ValidateScale compares a field
+type CompareStrategy int
+
+const (
+	// CompareAbsolute compares values directly with the field's absolute tolerance
+	CompareAbsolute CompareStrategy = iota
+	// CompareLinearRelative applies to log fields: both values are
+	// exponentiated back to linear space (base^x) and compared with
+	// LinearRelTolerance. An absolute tolerance on a log already bounds the
+	// ratio of the linear values, but only in log units; this strategy states
+	// the tolerance as a fraction of the original quantity (1e-5 = 0.001%),
+	// which is how measurement precision is usually quoted.
+	CompareLinearRelative
+	// CompareULP compares values by how many representable float64 values
+	// lie between them, allowing ULPTolerance units in the last place. It
+	// measures floating-point reproducibility across platforms rather than
+	// closeness in log units.
+	CompareULP
+)
+
+// FieldStrategies overrides the comparison strategy per field; fields not
+// present use CompareAbsolute
+var FieldStrategies = map[string]CompareStrategy{}
+
+// LinearRelTolerance is the relative tolerance used by CompareLinearRelative.
+// It allows for the 6dp rounding of logs, which is up to about 1.2e-6 in
+// linear terms for base 10.
+var LinearRelTolerance = 1e-5
+
+// ULPTolerance is the number of units in the last place CompareULP allows
+var ULPTolerance uint64 = 4
+
+// ULPDistance returns how many math.Nextafter steps separate a and b: 0 for
+// equal values (including +0 and -0) and math.MaxUint64 when either is NaN
+func ULPDistance(a, b float64) uint64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.MaxUint64
+	}
+	ia, ib := orderedBits(a), orderedBits(b)
+	if ia > ib {
+		return uint64(ia - ib)
+	}
+	return uint64(ib - ia)
+}
+
+// orderedBits maps a float64 onto an integer line on which adjacent
+// representable values differ by one, so distances can be taken directly
+// instead of stepping with math.Nextafter
+func orderedBits(f float64) int64 {
+	bits := int64(math.Float64bits(f))
+	if bits < 0 {
+		return math.MinInt64 - bits
+	}
+	return bits
+}
+
+// compareLinearRelative compares two logs in the given base by the relative
+// difference of their linear values, |base^(act-exp) - 1|
+func compareLinearRelative(expected, actual interface{}, base, relTol float64) bool {
+	expFloat, expOk := toFloat64(expected)
+	actFloat, actOk := toFloat64(actual)
+	if !expOk || !actOk {
+		return CompareValues(expected, actual)
+	}
+	return math.Abs(math.Pow(base, actFloat-expFloat)-1) < relTol
+}
 
 // ValidationResult represents the result of validating a scale
 type ValidationResult struct {
 	ScaleID    string
 	Passed     bool
 	Mismatches []string
+	// Cause is a one-line guess at what input led to the mismatches, from
+	// the dependency graph between fields; empty when nothing mismatched
+	Cause string
 }
 
 // CompareValues compares two values with tolerance for floats
 func CompareValues(expected, actual interface{}) bool {
+	return CompareValuesTol(expected, actual, Tolerance)
+}
+
+// CompareValuesTol compares two values using the given absolute tolerance for floats
+func CompareValuesTol(expected, actual interface{}, tol float64) bool {
 	if expected == nil && actual == nil {
 		return true
 	}
@@ -36,13 +147,39 @@ func CompareValues(expected, actual interface{}) bool {
 	actFloat, actOk := toFloat64(actual)
 	
 	if expOk && actOk {
-		return math.Abs(expFloat-actFloat) < Tolerance
+		return math.Abs(expFloat-actFloat) < tol
 	}
 	
 	// Handle string comparisons
 	return fmt.Sprintf("%v", expected) == fmt.Sprintf("%v", actual)
 }
 
+// CompareValuesRel compares two values using a relative tolerance |exp-act|/|exp| for floats.
+// When expected is zero the relative error is undefined, so it falls back to the absolute Tolerance.
+func CompareValuesRel(expected, actual interface{}, relTol float64) bool {
+	expFloat, expOk := toFloat64(expected)
+	actFloat, actOk := toFloat64(actual)
+	if !expOk || !actOk {
+		return CompareValues(expected, actual)
+	}
+
+	if expFloat == 0 {
+		return math.Abs(actFloat) < Tolerance
+	}
+	return math.Abs(expFloat-actFloat)/math.Abs(expFloat) < relTol
+}
+
+// CompareValuesULP compares two values allowing floats to be up to maxULPs
+// units in the last place apart
+func CompareValuesULP(expected, actual interface{}, maxULPs uint64) bool {
+	expFloat, expOk := toFloat64(expected)
+	actFloat, actOk := toFloat64(actual)
+	if !expOk || !actOk {
+		return CompareValues(expected, actual)
+	}
+	return ULPDistance(expFloat, actFloat) <= maxULPs
+}
+
 // toFloat64 attempts to convert an interface to float64
 func toFloat64(v interface{}) (float64, bool) {
 	switch val := v.(type) {
@@ -59,8 +196,64 @@ func toFloat64(v interface{}) (float64, bool) {
 	}
 }
 
-// ValidateScale validates a computed scale against expected values
-func ValidateScale(computed map[string]interface{}, expected map[string]interface{}) ValidationResult {
+// FieldTolerances maps a computed field name to the absolute tolerance used for it.
+// Fields not present in the map are compared using the default Tolerance.
+type FieldTolerances map[string]float64
+
+// ToleranceFor returns the tolerance for a field, falling back to the default
+func (t FieldTolerances) ToleranceFor(field string) float64 {
+	if tol, ok := t[field]; ok {
+		return tol
+	}
+	return Tolerance
+}
+
+// UniformTolerances returns tolerances applying tol to every computed field
+// that ValidateScale checks; derived fields keep the default Tolerance
+func UniformTolerances(tol float64) FieldTolerances {
+	return FieldTolerances{
+		"BaseScale":        tol,
+		"ScaleFactor":      tol,
+		"ScaleFactorPower": tol,
+		"Scale":            tol,
+		"LogScale":         tol,
+		"LogMeasure":       tol,
+	}
+}
+
+// ValidateExtraFields makes ValidateScale also check every other field
+// present in both the computed scale and the answer-key entry whose values
+// are comparable (both numbers, both strings or both booleans), so new
+// derived fields are validated without being listed. Fields only one side
+// has, such as answer-key notes, are still ignored.
+var ValidateExtraFields = false
+
+// extraComparableFields returns, sorted, the fields outside checked that
+// computed and expected both have with comparable non-nil values
+func extraComparableFields(computed, expected map[string]interface{}, checked []string) []string {
+	var fields []string
+	for field, expVal := range expected {
+		actVal, ok := computed[field]
+		if !ok || expVal == nil || actVal == nil || slices.Contains(checked, field) {
+			continue
+		}
+		_, expNum := toFloat64(expVal)
+		_, actNum := toFloat64(actVal)
+		_, expStr := expVal.(string)
+		_, actStr := actVal.(string)
+		_, expBool := expVal.(bool)
+		_, actBool := actVal.(bool)
+		if (expNum && actNum) || (expStr && actStr) || (expBool && actBool) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ValidateScale validates a computed scale against expected values.
+// tolerances may be nil to use the default Tolerance for every field.
+func ValidateScale(computed map[string]interface{}, expected map[string]interface{}, tolerances FieldTolerances) ValidationResult {
 	result := ValidationResult{
 		ScaleID:    computed["ScaleID"].(string),
 		Passed:     true,
@@ -68,50 +261,254 @@ func ValidateScale(computed map[string]interface{}, expected map[string]interfac
 	}
 	
 	computedFields := []string{"BaseScale", "ScaleFactor", "ScaleFactorPower", "Scale", "LogScale", "LogMeasure"}
+	computedFields = append(computedFields, validatedDerivedFields(expected)...)
+	if ValidateExtraFields {
+		computedFields = append(computedFields, extraComparableFields(computed, expected, computedFields)...)
+	}
+	var failedFields []string
 	
 	for _, field := range computedFields {
 		expVal := expected[field]
 		actVal := computed[field]
 		
-		if !CompareValues(expVal, actVal) {
+		// A nil log means it was taken of a non-positive or missing input; it
+		// only passes when the answer key explicitly expects null too
+		if actVal == nil && logFields[field] {
+			if _, present := expected[field]; present && expVal == nil {
+				continue
+			}
 			result.Passed = false
+			failedFields = append(failedFields, field)
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("%s: undefined (log of missing or non-positive input), expected %v", field, expVal))
+			continue
+		}
+		
+		tol := tolerances.ToleranceFor(field)
+		matched := CompareValuesTol(expVal, actVal, tol)
+		toleranceDesc := fmt.Sprintf("tolerance %g", tol)
+		if _, numeric := toFloat64(expVal); !numeric && expVal != nil {
+			toleranceDesc = "exact match required"
+		}
+		if logFields[field] && FieldStrategies[field] == CompareLinearRelative {
+			matched = compareLinearRelative(expVal, actVal, outputLogBase(computed), LinearRelTolerance)
+			toleranceDesc = fmt.Sprintf("linear relative tolerance %g", LinearRelTolerance)
+		}
+		if FieldStrategies[field] == CompareULP {
+			matched = CompareValuesULP(expVal, actVal, ULPTolerance)
+			toleranceDesc = fmt.Sprintf("tolerance %d ULPs", ULPTolerance)
+			expFloat, expOk := toFloat64(expVal)
+			actFloat, actOk := toFloat64(actVal)
+			if expOk && actOk {
+				toleranceDesc = fmt.Sprintf("%d ULPs apart, %s", ULPDistance(expFloat, actFloat), toleranceDesc)
+			}
+		}
+		if relativeFields[field] {
+			matched = matched || CompareValuesRel(expVal, actVal, RelTolerance)
+			toleranceDesc += fmt.Sprintf(", relative %g", RelTolerance)
+		}
+
+		if !matched {
+			if pct, ok := percentError(expVal, actVal); ok {
+				toleranceDesc = pct + ", " + toleranceDesc
+			}
+			result.Passed = false
+			failedFields = append(failedFields, field)
 			result.Mismatches = append(result.Mismatches, 
-				fmt.Sprintf("%s: expected %v, got %v", field, expVal, actVal))
+				fmt.Sprintf("%s: expected %v, got %v (%s)", field, expVal, actVal, toleranceDesc))
 		}
 	}
 	
+	result.Cause = ExplainMismatches(failedFields)
 	return result
 }
 
-// ValidateAllScales validates all computed scales against answer key
-func ValidateAllScales(computed []map[string]interface{}, answerKey *AnswerKey) (int, int, []ValidationResult) {
-	// Build lookup by ScaleID
-	expectedByID := make(map[string]map[string]interface{})
-	for _, s := range answerKey.Scales {
-		if scaleID, ok := s["ScaleID"].(string); ok {
-			expectedByID[scaleID] = s
+// fieldInputs is the dependency graph of the validated fields: each field
+// lists the values it is calculated from. Derived fields are not listed and
+// count as depending on every field here.
+var fieldInputs = map[string][]string{
+	"BaseScale":        {"the system's BaseScale"},
+	"ScaleFactor":      {"the system's ScaleFactor"},
+	"ScaleFactorPower": {"ScaleFactor", "Iteration"},
+	"Scale":            {"BaseScale", "ScaleFactorPower"},
+	"LogScale":         {"Scale", "LogBase"},
+	"LogMeasure":       {"Measure", "LogBase"},
+}
+
+// ExplainMismatches returns a one-line diagnosis for a scale whose given
+// fields mismatched. The root causes are the mismatched fields none of whose
+// inputs also mismatched; the rest are taken to be downstream of them. When
+// several roots share an input, that input is suggested alone.
+func ExplainMismatches(failed []string) string {
+	if len(failed) == 0 {
+		return ""
+	}
+	failedSet := make(map[string]bool, len(failed))
+	for _, f := range failed {
+		failedSet[f] = true
+	}
+	
+	var roots []string
+	for _, f := range failed {
+		inputs, known := fieldInputs[f]
+		isRoot := true
+		for dep := range fieldInputs {
+			if failedSet[dep] && (!known || slices.Contains(inputs, dep)) {
+				isRoot = false
+				break
+			}
+		}
+		if isRoot {
+			roots = append(roots, f)
 		}
 	}
 	
-	passCount := 0
-	failCount := 0
-	failures := []ValidationResult{}
+	downstream := ""
+	if len(failed) > len(roots) {
+		downstream = " and downstream fields"
+	}
+	subject := strings.Join(roots, " and ") + downstream
 	
-	for _, comp := range computed {
-		scaleID, _ := comp["ScaleID"].(string)
-		expected, found := expectedByID[scaleID]
-		
-		if !found {
-			failCount++
-			failures = append(failures, ValidationResult{
-				ScaleID:    scaleID,
-				Passed:     false,
-				Mismatches: []string{"Not found in answer key"},
-			})
-			continue
+	if len(roots) > 1 {
+		var shared []string
+		for _, input := range fieldInputs[roots[0]] {
+			common := true
+			for _, r := range roots[1:] {
+				common = common && slices.Contains(fieldInputs[r], input)
+			}
+			if common {
+				shared = append(shared, input)
+			}
+		}
+		if len(shared) > 0 {
+			return fmt.Sprintf("%s differ: check %s", subject, strings.Join(shared, " or "))
+		}
+	}
+	
+	hints := make([]string, 0, len(roots))
+	for _, r := range roots {
+		hint := "check the derived field definition"
+		if inputs, ok := fieldInputs[r]; ok {
+			hint = "check " + strings.Join(inputs, " or ")
+		}
+		if len(roots) > 1 {
+			hint += " for " + r
+		}
+		hints = append(hints, hint)
+	}
+	verb := "differs"
+	if len(roots) > 1 || downstream != "" {
+		verb = "differ"
+	}
+	return fmt.Sprintf("%s %s: %s", subject, verb, strings.Join(hints, "; "))
+}
+
+// outputLogBase returns the log base recorded in an output map, or DefaultLogBase
+func outputLogBase(m map[string]interface{}) float64 {
+	if base, ok := toFloat64(m["LogBase"]); ok && base > 0 && base != 1 {
+		return base
+	}
+	return DefaultLogBase
+}
+
+// percentError formats the difference between actual and expected as a
+// percentage of expected, e.g. "0.40%". ok is false when either value is not
+// numeric or expected is zero.
+func percentError(expected, actual interface{}) (string, bool) {
+	expFloat, expOk := toFloat64(expected)
+	actFloat, actOk := toFloat64(actual)
+	if !expOk || !actOk || expFloat == 0 {
+		return "", false
+	}
+	
+	pct := math.Abs(actFloat-expFloat) / math.Abs(expFloat) * 100
+	if pct < 0.01 {
+		return fmt.Sprintf("%.2g%%", pct), true
+	}
+	return fmt.Sprintf("%.2f%%", pct), true
+}
+
+// ValidateAllScales validates all computed scales against answer key using
+// the given number of goroutines (0 = one per CPU). Failures are sorted by
+// ScaleID, so the result is the same for any worker count.
+func ValidateAllScales(computed []map[string]interface{}, answerKey *AnswerKey, tolerances FieldTolerances,
+	workers int) (int, int, []ValidationResult) {
+	return ValidateAgainstIndex(computed, IndexAnswerKey(answerKey), tolerances, workers)
+}
+
+// ValidateAgainstIndex is ValidateAllScales for an answer key that has
+// already been indexed, e.g. by StreamAnswerKey
+func ValidateAgainstIndex(computed []map[string]interface{}, index *AnswerKeyIndex, tolerances FieldTolerances,
+	workers int) (int, int, []ValidationResult) {
+	passCount, failCount, failures, _ := ValidateAgainstIndexLimit(computed, index, tolerances, 0, workers)
+	return passCount, failCount, failures
+}
+
+// validateComputed validates one computed scale against the expected scale
+// with the same ScaleID, failing it when the answer key has none
+func validateComputed(comp map[string]interface{}, expectedByID map[string]map[string]interface{},
+	tolerances FieldTolerances) ValidationResult {
+	scaleID, _ := comp["ScaleID"].(string)
+	expected, found := expectedByID[scaleID]
+	if !found {
+		return ValidationResult{
+			ScaleID:    scaleID,
+			Passed:     false,
+			Mismatches: []string{"Not found in answer key"},
+		}
+	}
+	return ValidateScale(comp, expected, tolerances)
+}
+
+// ValidateAgainstIndexLimit is ValidateAgainstIndex that stops once
+// maxFailures scales have failed (0 = no limit), for failing fast on large
+// answer keys. stopped reports whether scales were left unvalidated; the
+// counts then cover only the scales up to the maxFailures-th failure in input
+// order, exactly as if they had been validated one at a time.
+func ValidateAgainstIndexLimit(computed []map[string]interface{}, index *AnswerKeyIndex, tolerances FieldTolerances,
+	maxFailures, workers int) (passCount, failCount int, failures []ValidationResult, stopped bool) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(computed) {
+		workers = len(computed)
+	}
+	
+	results := make([]ValidationResult, len(computed))
+	var failed atomic.Int64
+	indices := make(chan int)
+	
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = validateComputed(computed[i], index.Scales, tolerances)
+				if !results[i].Passed {
+					failed.Add(1)
+				}
+			}
+		}()
+	}
+	
+	// Scales are handed out in input order, so once maxFailures have failed
+	// every scale up to the maxFailures-th failure in input order is done
+	for i := range computed {
+		if maxFailures > 0 && int(failed.Load()) >= maxFailures {
+			break
+		}
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	
+	failures = []ValidationResult{}
+	for _, result := range results {
+		if maxFailures > 0 && failCount >= maxFailures {
+			stopped = true
+			break
 		}
-		
-		result := ValidateScale(comp, expected)
 		if result.Passed {
 			passCount++
 		} else {
@@ -119,6 +516,112 @@ func ValidateAllScales(computed []map[string]interface{}, answerKey *AnswerKey)
 			failures = append(failures, result)
 		}
 	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].ScaleID < failures[j].ScaleID
+	})
 	
-	return passCount, failCount, failures
+	return passCount, failCount, failures, stopped
+}
+
+// FindMissingScales returns a failure for each answer-key ScaleID that does
+// not appear in computed, in answer-key order
+func FindMissingScales(computed []map[string]interface{}, answerKey *AnswerKey) []ValidationResult {
+	return FindMissingInIndex(computed, IndexAnswerKey(answerKey))
+}
+
+// FindMissingInIndex is FindMissingScales for an indexed answer key
+func FindMissingInIndex(computed []map[string]interface{}, index *AnswerKeyIndex) []ValidationResult {
+	computedIDs := make(map[string]bool, len(computed))
+	for _, comp := range computed {
+		if scaleID, ok := comp["ScaleID"].(string); ok {
+			computedIDs[scaleID] = true
+		}
+	}
+
+	missing := []ValidationResult{}
+	for _, scaleID := range index.IDs {
+		if computedIDs[scaleID] {
+			continue
+		}
+		missing = append(missing, ValidationResult{
+			ScaleID:    scaleID,
+			Passed:     false,
+			Mismatches: []string{"expected in answer key but not computed"},
+		})
+	}
+	return missing
+}
+
+// CompareResults diffs two results files scale by scale, matched by ScaleID.
+// Every field present in either scale is compared with tol; scales present in
+// only one of the files are reported as such. Only differing scales are
+// returned, sorted by ScaleID.
+func CompareResults(a, b *TestResults, tol float64) []ValidationResult {
+	aByID := indexScalesByID(a.Scales)
+	bByID := indexScalesByID(b.Scales)
+	
+	ids := make([]string, 0, len(aByID)+len(bByID))
+	for id := range aByID {
+		ids = append(ids, id)
+	}
+	for id := range bByID {
+		if _, ok := aByID[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	
+	var diffs []ValidationResult
+	for _, id := range ids {
+		aScale, inA := aByID[id]
+		bScale, inB := bByID[id]
+		
+		result := ValidationResult{ScaleID: id, Passed: true, Mismatches: []string{}}
+		switch {
+		case !inA:
+			result.Mismatches = append(result.Mismatches, "only in second results")
+		case !inB:
+			result.Mismatches = append(result.Mismatches, "only in first results")
+		default:
+			for _, field := range unionKeys(aScale, bScale) {
+				if !CompareValuesTol(aScale[field], bScale[field], tol) {
+					result.Mismatches = append(result.Mismatches,
+						fmt.Sprintf("%s: %v -> %v", field, aScale[field], bScale[field]))
+				}
+			}
+		}
+		
+		if len(result.Mismatches) > 0 {
+			result.Passed = false
+			diffs = append(diffs, result)
+		}
+	}
+	return diffs
+}
+
+// indexScalesByID builds a lookup of output maps by ScaleID
+func indexScalesByID(scales []map[string]interface{}) map[string]map[string]interface{} {
+	byID := make(map[string]map[string]interface{}, len(scales))
+	for _, s := range scales {
+		if id, ok := s["ScaleID"].(string); ok {
+			byID[id] = s
+		}
+	}
+	return byID
+}
+
+// unionKeys returns the sorted set of keys present in either map
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool, len(a))
+	var keys []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,80 @@
+package rulebook
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testDataPath returns the path of a file in the repo's shared test-data directory
+func testDataPath(name string) string {
+	return filepath.Join("..", "..", "..", "test-data", name)
+}
+
+// loadComputedTestData loads the repo's test data and computes every base and
+// test scale, returning the output maps, the answer key and the systems
+func loadComputedTestData(t testing.TB) ([]map[string]interface{}, *AnswerKey, SystemsMap) {
+	t.Helper()
+	baseData, err := LoadBaseData(testDataPath("base-data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testInput, err := LoadTestInput(testDataPath("test-input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	answerKey, err := LoadAnswerKey(testDataPath("answer-key.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	systems := BuildSystemsMap(baseData.Systems)
+	var computed []map[string]interface{}
+	for _, scales := range [][]Scale{baseData.Scales, testInput.Scales} {
+		for i := range scales {
+			if err := scales[i].CalculateAllFields(systems); err != nil {
+				t.Fatal(err)
+			}
+			computed = append(computed, scales[i].ToOutputMap())
+		}
+	}
+	return computed, answerKey, systems
+}
+
+func TestValidateAgainstIndexParallelMatchesSerial(t *testing.T) {
+	computed, answerKey, _ := loadComputedTestData(t)
+
+	// Break every third expected Scale and add a scale the key lacks, so
+	// there are failures to order and to stop at
+	for i, expected := range answerKey.Scales {
+		if i%3 == 0 {
+			expected["Scale"] = -1.0
+		}
+	}
+	computed = append(computed, map[string]interface{}{"ScaleID": "Unknown_0"})
+	index := IndexAnswerKey(answerKey)
+
+	for _, maxFailures := range []int{0, 1, 5} {
+		wantPass, wantFail, wantFailures, wantStopped := ValidateAgainstIndexLimit(computed, index, nil, maxFailures, 1)
+		if wantFail == 0 {
+			t.Fatalf("maxFailures %d: expected failures in the serial run", maxFailures)
+		}
+		if !sort.SliceIsSorted(wantFailures, func(i, j int) bool {
+			return wantFailures[i].ScaleID < wantFailures[j].ScaleID
+		}) {
+			t.Errorf("maxFailures %d: failures not sorted by ScaleID", maxFailures)
+		}
+
+		for _, workers := range []int{0, 2, 4, 16, 1000} {
+			pass, fail, failures, stopped := ValidateAgainstIndexLimit(computed, index, nil, maxFailures, workers)
+			if pass != wantPass || fail != wantFail || stopped != wantStopped {
+				t.Errorf("maxFailures %d, workers %d: got %d/%d stopped=%v, want %d/%d stopped=%v",
+					maxFailures, workers, pass, fail, stopped, wantPass, wantFail, wantStopped)
+			}
+			if !reflect.DeepEqual(failures, wantFailures) {
+				t.Errorf("maxFailures %d, workers %d: failures differ from the serial run", maxFailures, workers)
+			}
+		}
+	}
+}
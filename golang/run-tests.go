@@ -4,24 +4,34 @@
 // 1. Load base-data.json (for systems configuration + base scales)
 // 2. Load test-input.json (raw facts only)
 // 3. Compute derived values for test scales
-// 4. Output results to test-results/golang-results.json
+// 4. Output results to test-results/golang-results.json (and .csv)
 // 5. Validate against answer-key.json
 // 6. Display with unified visualization (all 8 iterations, colors, ASCII plots)
 
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"erb-power-laws/pkg/rulebook"
 )
 
-// ANSI colors
-const (
+// ANSI colors (cleared by configureColor when color output is disabled)
+var (
 	green   = "\033[92m"
 	yellow  = "\033[93m"
 	cyan    = "\033[96m"
@@ -32,14 +42,342 @@ const (
 	magenta = "\033[95m"
 )
 
+// configureColor enables or disables ANSI color codes for all report output
+func configureColor(enabled bool) {
+	rulebook.PlotColor = enabled
+	if enabled {
+		return
+	}
+	for _, code := range []*string{&green, &yellow, &cyan, &red, &dim, &reset, &bold, &magenta} {
+		*code = ""
+	}
+}
+
 // Plot characters
 const (
+	plotInterp   = "◇"
+	plotBand     = "░"
+	plotErrorBar = "¦"
+)
+
+// Point markers, set by configureMarkers. Each is exactly one rune so every
+// plot grid cell stays one column wide.
+var (
 	plotActual      = "●"
 	plotProjected   = "◌"
 	plotTheoretical = "·"
 )
 
+// markerPresets are the -markers choices: actual, projected and theoretical
+var markerPresets = map[string][3]string{
+	"unicode": {"●", "◌", "·"},
+	"ascii":   {"#", "o", "."},
+}
+
+// configureMarkers applies a marker preset and then any individual
+// overrides (empty means keep the preset's marker)
+func configureMarkers(preset, actual, projected, theoretical string) error {
+	markers, ok := markerPresets[preset]
+	if !ok {
+		return fmt.Errorf("unknown marker preset %q (expected ascii or unicode)", preset)
+	}
+	for i, override := range []string{actual, projected, theoretical} {
+		if override == "" {
+			continue
+		}
+		if utf8.RuneCountInString(override) != 1 || strings.TrimSpace(override) == "" {
+			return fmt.Errorf("marker %q must be a single visible character", override)
+		}
+		markers[i] = override
+	}
+	plotActual, plotProjected, plotTheoretical = markers[0], markers[1], markers[2]
+	return nil
+}
+
+// Terminal overlay styling. Each system gets a palette color hashed from its
+// ID and a symbol by its position in the plotted set, so systems stay
+// distinguishable by symbol alone when color is disabled.
+var (
+	overlayPalette = []string{
+		"\033[94m", "\033[93m", "\033[96m", "\033[91m", "\033[92m",
+		"\033[95m", "\033[33m", "\033[36m", "\033[35m", "\033[32m",
+	}
+	overlaySymbols = []string{"o", "x", "+", "*", "#", "@", "%", "&", "=", "$"}
+)
+
+// Report styles selected by -format
+const (
+	formatTable    = "table"
+	formatPlotOnly = "plot-only"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatQuiet    = "quiet"
+)
+
+var outputFormats = []string{formatTable, formatPlotOnly, formatJSON, formatCSV, formatQuiet}
+
+// Plot size limits
+const (
+	defaultPlotWidth  = 50
+	defaultPlotHeight = 12
+	minPlotWidth      = 10
+	minPlotHeight     = 5
+	plotGutterWidth   = 11 // y-axis labels and border to the left of the grid
+	svgPlotWidth      = 640
+	svgPlotHeight     = 420
+)
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// Watch mode settings
+const (
+	watchPollInterval = 500 * time.Millisecond
+	watchRetries      = 3
+	clearScreen       = "\033[H\033[2J"
+)
+
+// reportOptions controls how the full report is rendered
+type reportOptions struct {
+	plotWidth        int
+	plotHeight       int
+	outlierThreshold float64
+	residuals        bool
+	heatmap          bool
+	bootstrap        int
+	bootstrapSeed    int64
+	convergence      bool
+	sortSystems      string
+	systemFileOrder  []string
+	minPassRate      float64
+	timing           bool
+	explain          bool
+	stoppedEarly     bool
+	computeTimes     map[string]time.Duration
+	overlay          map[string][]map[string]interface{}
+	warnings         []reportWarning
+	failOnWarnings   bool
+}
+
+// warningKind names what a report warning is about
+type warningKind string
+
+const (
+	warnMetadata      warningKind = "metadata"
+	warnExtrapolation warningKind = "extrapolation"
+	warnOutliers      warningKind = "outliers"
+	warnNarrowRange   warningKind = "narrow-range"
+	warnMonotonicity  warningKind = "monotonicity"
+	warnConvergence   warningKind = "convergence"
+)
+
+// reportWarning is one warning shown in the report. systemID is empty for
+// warnings not tied to a single system; message is the text after the ⚠.
+type reportWarning struct {
+	kind     warningKind
+	systemID string
+	message  string
+}
+
+// warningsOf returns the warnings of one kind, limited to systemID unless it is empty
+func (opts reportOptions) warningsOf(kind warningKind, systemID string) []reportWarning {
+	var matched []reportWarning
+	for _, w := range opts.warnings {
+		if w.kind == kind && (systemID == "" || w.systemID == systemID) {
+			matched = append(matched, w)
+		}
+	}
+	return matched
+}
+
 func main() {
+	format := flag.String("format", formatTable, "report style: table, plot-only, json or csv (results to stdout instead of files), or quiet (exit code only)")
+	plotWidth := flag.Int("plot-width", defaultPlotWidth, "width of ASCII plots in characters (min 10)")
+	plotHeight := flag.Int("plot-height", defaultPlotHeight, "height of ASCII plots in rows (min 5)")
+	precision := flag.Int("precision", rulebook.DefaultRounding.Digits, "decimal places for output values (fewer than 6 can fail validation)")
+	sigFigs := flag.Int("sig-figs", 0, "round output to this many significant figures instead of fixed decimals")
+	dryRun := flag.Bool("dry-run", false, "compute, validate and report without writing any results files")
+	powerBySquaring := flag.Bool("power-by-squaring", false, "compute ScaleFactor^Iteration by repeated squaring instead of math.Pow for non-negative integer iterations")
+	errorBars := flag.Bool("error-bars", false, "draw ±1σ error bars on plotted points aggregated from Replicates")
+	normalize := flag.Bool("normalize", false, "plot each system relative to its first actual point so slopes compare on shared axes (rendering only)")
+	markers := flag.String("markers", "unicode", "plot marker preset: unicode (● ◌ ·) or ascii (# o .)")
+	markerActual := flag.String("marker-actual", "", "single character marking actual points (overrides -markers)")
+	markerProjected := flag.String("marker-projected", "", "single character marking projected points (overrides -markers)")
+	markerTheoretical := flag.String("marker-theoretical", "", "single character drawing the theoretical line (overrides -markers)")
+	fitTrimHead := flag.Int("fit-trim-head", 0, "leave this many lowest-iteration actual points out of slope fits")
+	fitTrimTail := flag.Int("fit-trim-tail", 0, "leave this many highest-iteration actual points out of slope fits")
+	residuals := flag.Bool("residuals", false, "print a residuals-vs-iteration plot under each log-log plot")
+	heatmap := flag.Bool("heatmap", false, "print a systems × iterations heatmap of |residual| from the theoretical lines")
+	interpolate := flag.Bool("interpolate", false, "fill iterations missing between actual points by log-log interpolation")
+	var measureTargets stringList
+	flag.Var(&measureTargets, "predict-measure", "SYSTEM=MEASURE: report the Scale and iteration at which the system reaches MEASURE on its theoretical line through the last actual point (repeatable)")
+	projectIters := flag.String("project", "", "comma-separated iterations to project from the theoretical slope (e.g. 4,5,6,7)")
+	anchor := flag.String("anchor", string(rulebook.AnchorFirst),
+		"where -project pins the theoretical line: first or last actual point, or fit (least squares through all actuals)")
+	var inputPaths stringList
+	flag.Var(&inputPaths, "input", "test-input file or http(s) URL to load (repeatable; default test-data/test-input.json)")
+	baseDataPath := flag.String("base-data", "", "base-data file or http(s) URL to load (default test-data/base-data.json)")
+	bootstrap := flag.Int("bootstrap", 0, "report a bootstrapped 95% confidence interval on each fractal dimension using this many resamples")
+	bootstrapSeed := flag.Int64("bootstrap-seed", 1, "random seed for -bootstrap resampling")
+	convergence := flag.Bool("convergence", false, "show each fractal's local dimension between consecutive actual points and whether it is converging")
+	convergenceTol := flag.Float64("convergence-tol", rulebook.DimensionConvergenceTolerance, "largest change between the last two local dimensions that -convergence counts as converged")
+	outlierThreshold := flag.Float64("outlier-threshold", 0.1, "flag actual points whose log residual from the theoretical line exceeds this")
+	tolerance := flag.Float64("tolerance", rulebook.DefaultTolerance,
+		"absolute tolerance for validating values (default from $VERITASIUM_TOLERANCE if set; the answer key assumes the default)")
+	logCompare := flag.String("log-compare", "absolute",
+		"how to validate LogScale/LogMeasure: absolute (in log units), linear (relative, after exponentiating) or ulp (units in the last place)")
+	ulps := flag.Uint64("ulps", rulebook.ULPTolerance, "units in the last place allowed by -log-compare ulp")
+	validateExtra := flag.Bool("validate-extra", false, "also validate every other field present in both the answer key and the computed scale")
+	slopeTolerance := flag.Float64("slope-tolerance", 0.05, "maximum allowed difference between fitted and theoretical slopes")
+	slopeDimensionTol := flag.Float64("slope-dimension-tol", rulebook.SlopeDimensionTolerance, "maximum allowed |TheoreticalLogLogSlope + FractalDimension| before a system gets a metadata warning at load")
+	comparePath := flag.String("compare", "", "diff this run against a previous results file or http(s) URL")
+	onlyFailures := flag.Bool("only-failures", false, "skip the per-system tables and plots; show only validation failures and a one-line summary")
+	baselineWrite := flag.String("baseline-write", "", "save this run's results as a golden baseline to this path (after any -baseline-check)")
+	baselineCheck := flag.String("baseline-check", "", "compare this run against a baseline saved by -baseline-write and fail on any drift beyond tolerance")
+	junitPath := flag.String("junit", "", "write a JUnit XML validation report to this path")
+	jsonlPath := flag.String("jsonl", "", "write the results as JSON lines (one scale per line) to this path")
+	summaryJSONPath := flag.String("summary-json", "", "write a compact JSON rollup (per-system slopes, R², pass/fail and warning counts) to this path")
+	parquetPath := flag.String("parquet", "", "write the results as a Parquet file to this path (needs a build with -tags parquet)")
+	htmlPath := flag.String("html", "", "write a self-contained HTML report with tables and plots to this path")
+	svgDir := flag.String("svg-dir", "", "directory to write one SVG log-log plot per system")
+	gnuplotDir := flag.String("gnuplot-dir", "", "directory to write one gnuplot data file per system")
+	overlayPath := flag.String("overlay", "", "write an SVG log-log plot overlaying several systems to this path")
+	var overlaySystems stringList
+	flag.Var(&overlaySystems, "overlay-system", "system to include in the -overlay plot (repeatable; default all)")
+	overlayASCII := flag.Bool("overlay-ascii", false, "print the -overlay-system selection as one terminal log-log plot")
+	keepLastDup := flag.Bool("keep-last-duplicate", false, "keep the last of several scales sharing a ScaleID in one file instead of failing")
+	var systemFilter stringList
+	flag.Var(&systemFilter, "system", "only compute, validate and display this system (repeatable; default all)")
+	iterMin := flag.Int("iter-min", 0, "only include scales with at least this iteration")
+	iterMax := flag.Int("iter-max", 0, "only include scales with at most this iteration")
+	var answerKeys stringList
+	flag.Var(&answerKeys, "answer-key", "answer key file or http(s) URL to validate against, as PATH or PATH@TOLERANCE (repeatable; the strictest decides the exit code)")
+	var derivedNames stringList
+	flag.Var(&derivedNames, "derived", "add a built-in derived field to the output, e.g. density (repeatable)")
+	explain := flag.Bool("explain", false, "print a likely cause under each validation failure")
+	failOnWarnings := flag.Bool("fail-on-warnings", false, "exit with code 1 when the report has any ⚠ warning (metadata, outliers, narrow range, monotonicity, ...)")
+	timing := flag.Bool("timing", false, "show each system's computation time in the summary and add computeMillis to the results JSON")
+	minScaleDecades := flag.Float64("min-scale-decades", rulebook.MinScaleDecades, "warn when a system's actual points span fewer decades of Scale than this")
+	minPassRate := flag.Float64("min-pass-rate", 1, "fraction of validated scales (0 to 1) that must pass for exit code 0")
+	sortSystems := flag.String("sort-systems", "id", "order of systems in the report: id, slope, class, or file (base-data order)")
+	generate := flag.String("generate", "", "write a synthetic test input for this system along its theoretical line, then exit")
+	generateIters := flag.Int("generate-iterations", 8, "number of iterations (from 0) for -generate")
+	generateNoise := flag.Float64("generate-noise", 0, "standard deviation of Gaussian noise added to each LogMeasure by -generate")
+	generateSeed := flag.Int64("generate-seed", 1, "random seed for -generate noise")
+	generateOut := flag.String("generate-out", "", "file for -generate to write (default stdout)")
+	quiet := flag.Bool("quiet", false, "suppress the progress counter printed to stderr during large computations")
+	listSystems := flag.Bool("list-systems", false, "print the systems defined in base-data.json and exit without computing")
+	watch := flag.Bool("watch", false, "rerun whenever a data file changes, until interrupted")
+	traceID := flag.String("trace", "", "print each intermediate calculation step for this ScaleID")
+	stream := flag.Bool("stream", false, "stream the answer key element by element (for very large JSON answer keys)")
+	strict := flag.Bool("strict", false, "fail every answer-key scale that was not computed")
+	maxFailures := flag.Int("max-failures", 0, "stop validating after this many failed scales (0 = validate every scale)")
+	workers := flag.Int("workers", 0, "number of goroutines used to compute and validate scales (0 = one per CPU)")
+	noColor := flag.Bool("no-color", false, "disable ANSI colors (also disabled when stdout is not a terminal or NO_COLOR is set)")
+	flag.Parse()
+
+	configureColor(!*noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout))
+
+	opts := reportOptions{
+		plotWidth:        *plotWidth,
+		plotHeight:       *plotHeight,
+		outlierThreshold: *outlierThreshold,
+		residuals:        *residuals,
+		heatmap:          *heatmap,
+		bootstrap:        *bootstrap,
+		bootstrapSeed:    *bootstrapSeed,
+		convergence:      *convergence,
+		sortSystems:      *sortSystems,
+		minPassRate:      *minPassRate,
+		timing:           *timing,
+		explain:          *explain,
+		failOnWarnings:   *failOnWarnings,
+	}
+	if opts.minPassRate < 0 || opts.minPassRate > 1 {
+		fmt.Printf("%sError: Invalid -min-pass-rate value %g (expected 0 to 1)%s\n", red, opts.minPassRate, reset)
+		os.Exit(1)
+	}
+	if err := configureMarkers(*markers, *markerActual, *markerProjected, *markerTheoretical); err != nil {
+		fmt.Printf("%sError: %v%s\n", red, err, reset)
+		os.Exit(1)
+	}
+	if !slices.Contains(outputFormats, *format) {
+		fmt.Printf("%sError: Invalid -format value %q (expected %s)%s\n", red, *format, strings.Join(outputFormats, ", "), reset)
+		os.Exit(1)
+	}
+	if *onlyFailures && *format != formatTable {
+		fmt.Printf("%sError: -only-failures only applies to -format %s%s\n", red, formatTable, reset)
+		os.Exit(1)
+	}
+	if !slices.Contains(systemSortKeys, opts.sortSystems) {
+		fmt.Printf("%sError: Invalid -sort-systems value %q (expected %s)%s\n", red, opts.sortSystems, strings.Join(systemSortKeys, ", "), reset)
+		os.Exit(1)
+	}
+	if !flagWasSet("plot-width") {
+		opts.plotWidth = autoPlotWidth()
+	}
+	opts.plotWidth = clampInt(opts.plotWidth, minPlotWidth)
+	opts.plotHeight = clampInt(opts.plotHeight, minPlotHeight)
+
+	rulebook.KeepLastDuplicates = *keepLastDup
+	rulebook.MinScaleDecades = *minScaleDecades
+	if *fitTrimHead < 0 || *fitTrimTail < 0 {
+		fmt.Printf("%sError: -fit-trim-head and -fit-trim-tail must not be negative%s\n", red, reset)
+		os.Exit(1)
+	}
+	rulebook.FitTrimHead = *fitTrimHead
+	rulebook.FitTrimTail = *fitTrimTail
+	rulebook.PlotErrorBars = *errorBars
+	rulebook.PlotNormalize = *normalize
+	rulebook.PowerBySquaring = *powerBySquaring
+	rulebook.SlopeDimensionTolerance = *slopeDimensionTol
+	rulebook.ValidateExtraFields = *validateExtra
+	rulebook.DimensionConvergenceTolerance = *convergenceTol
+	rulebook.OutputRounding = rulebook.Rounding{Mode: rulebook.RoundDecimalPlaces, Digits: *precision}
+	if *sigFigs > 0 {
+		rulebook.OutputRounding = rulebook.Rounding{Mode: rulebook.RoundSignificantFigures, Digits: *sigFigs}
+	}
+
+	if err := configureTolerance(*tolerance); err != nil {
+		fmt.Printf("%sError: %v%s\n", red, err, reset)
+		os.Exit(1)
+	}
+
+	for _, name := range derivedNames {
+		field, ok := rulebook.BuiltinDerivedFields[strings.ToLower(name)]
+		if !ok {
+			fmt.Printf("%sError: Unknown -derived field %q%s\n", red, name, reset)
+			os.Exit(1)
+		}
+		if err := rulebook.RegisterDerivedField(field); err != nil {
+			fmt.Printf("%sError: %v%s\n", red, err, reset)
+			os.Exit(1)
+		}
+	}
+
+	switch *logCompare {
+	case "absolute":
+	case "linear":
+		rulebook.FieldStrategies["LogScale"] = rulebook.CompareLinearRelative
+		rulebook.FieldStrategies["LogMeasure"] = rulebook.CompareLinearRelative
+	case "ulp":
+		rulebook.FieldStrategies["LogScale"] = rulebook.CompareULP
+		rulebook.FieldStrategies["LogMeasure"] = rulebook.CompareULP
+		rulebook.ULPTolerance = *ulps
+	default:
+		fmt.Printf("%sError: Invalid -log-compare value %q (expected absolute, linear or ulp)%s\n", red, *logCompare, reset)
+		os.Exit(1)
+	}
+
 	// Find project root (parent of golang directory)
 	execPath, _ := os.Getwd()
 	projectRoot := filepath.Dir(execPath)
@@ -49,323 +387,1632 @@ func main() {
 		projectRoot = execPath
 	}
 
-	// Paths
-	testDataDir := filepath.Join(projectRoot, "test-data")
-	testResultsDir := filepath.Join(projectRoot, "test-results")
-
-	baseDataPath := filepath.Join(testDataDir, "base-data.json")
-	testInputPath := filepath.Join(testDataDir, "test-input.json")
-	answerKeyPath := filepath.Join(testDataDir, "answer-key.json")
-	resultsPath := filepath.Join(testResultsDir, "golang-results.json")
+	// Paths
+	testDataDir := filepath.Join(projectRoot, "test-data")
+	testResultsDir := filepath.Join(projectRoot, "test-results")
+
+	cfg := runConfig{
+		baseDataPath:   filepath.Join(testDataDir, "base-data.json"),
+		inputPaths:     inputPaths,
+		answerKeyPath:  filepath.Join(testDataDir, "answer-key.json"),
+		testResultsDir: testResultsDir,
+		resultsPath:    filepath.Join(testResultsDir, "golang-results.json"),
+		resultsCSVPath: filepath.Join(testResultsDir, "golang-results.csv"),
+		dryRun:         *dryRun,
+		interpolate:    *interpolate,
+		slopeTolerance: *slopeTolerance,
+		comparePath:    *comparePath,
+		baselineWrite:  *baselineWrite,
+		onlyFailures:   *onlyFailures,
+		baselineCheck:  *baselineCheck,
+		junitPath:      *junitPath,
+		jsonlPath:      *jsonlPath,
+		parquetPath:    *parquetPath,
+		summaryPath:    *summaryJSONPath,
+		htmlPath:       *htmlPath,
+		svgDir:         *svgDir,
+		gnuplotDir:     *gnuplotDir,
+		overlayPath:    *overlayPath,
+		overlaySystems: overlaySystems,
+		overlayASCII:   *overlayASCII,
+		format:         *format,
+		workers:        *workers,
+		progress:       !*quiet && isTerminal(os.Stderr),
+		maxFailures:    *maxFailures,
+		strict:         *strict,
+		stream:         *stream,
+		traceID:        *traceID,
+		filter:         rulebook.ScaleFilter{Systems: systemFilter},
+		opts:           opts,
+	}
+	if *baseDataPath != "" {
+		cfg.baseDataPath = *baseDataPath
+	}
+	if len(cfg.inputPaths) == 0 {
+		cfg.inputPaths = stringList{filepath.Join(testDataDir, "test-input.json")}
+	}
+	if flagWasSet("iter-min") {
+		cfg.filter.IterMin = iterMin
+	}
+	if flagWasSet("iter-max") {
+		cfg.filter.IterMax = iterMax
+	}
+	if cfg.filter.IterMin != nil && cfg.filter.IterMax != nil && *iterMin > *iterMax {
+		fmt.Printf("%sError: -iter-min %d is greater than -iter-max %d%s\n", red, *iterMin, *iterMax, reset)
+		os.Exit(1)
+	}
+	if *projectIters != "" {
+		iterations, err := parseIntList(*projectIters)
+		if err != nil {
+			fmt.Printf("%sError: Invalid -project value: %v%s\n", red, err, reset)
+			os.Exit(1)
+		}
+		cfg.projectIters = iterations
+	}
+	targets, err := parseMeasureTargets(measureTargets)
+	if err != nil {
+		fmt.Printf("%sError: Invalid -predict-measure value: %v%s\n", red, err, reset)
+		os.Exit(1)
+	}
+	cfg.measureTargets = targets
+	projectionAnchor, err := rulebook.ParseProjectionAnchor(*anchor)
+	if err != nil {
+		fmt.Printf("%sError: Invalid -anchor value: %v%s\n", red, err, reset)
+		os.Exit(1)
+	}
+	cfg.anchor = projectionAnchor
+	if len(answerKeys) > 0 {
+		gates, err := parseAnswerKeyGates(answerKeys)
+		if err != nil {
+			fmt.Printf("%sError: Invalid -answer-key value: %v%s\n", red, err, reset)
+			os.Exit(1)
+		}
+		// The strictest key is the primary validation and decides the exit code
+		strictest, _ := rulebook.StrictestRequiredGate(gates)
+		cfg.answerKeyPath = strictest.Path
+		if strictest.Tolerance > 0 {
+			cfg.tolerances = rulebook.UniformTolerances(strictest.Tolerance)
+		}
+		if len(gates) > 1 {
+			cfg.gates = gates
+		}
+	}
+
+	if *generate != "" {
+		err := writeSyntheticInput(cfg.baseDataPath, *generate, *generateIters, *generateNoise, *generateSeed, *generateOut)
+		if err != nil {
+			fmt.Printf("%sError: %v%s\n", red, err, reset)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *listSystems {
+		if err := printSystemList(cfg.baseDataPath); err != nil {
+			fmt.Printf("%sError: %v%s\n", red, err, reset)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *watch {
+		watchAndRun(cfg)
+		return
+	}
+
+	exitCode, err := runTests(cfg)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", red, err, reset)
+		os.Exit(1)
+	}
+	os.Exit(exitCode)
+}
+
+// runConfig holds the resolved paths and options for one test run
+type runConfig struct {
+	baseDataPath   string
+	inputPaths     []string
+	answerKeyPath  string
+	tolerances     rulebook.FieldTolerances
+	gates          []rulebook.AnswerKeyGate
+	testResultsDir string
+	resultsPath    string
+	resultsCSVPath string
+
+	dryRun         bool
+	interpolate    bool
+	projectIters   []int
+	measureTargets []measureTarget
+	anchor         rulebook.ProjectionAnchor
+	slopeTolerance float64
+	comparePath    string
+	baselineWrite  string
+	onlyFailures   bool
+	baselineCheck  string
+	junitPath      string
+	jsonlPath      string
+	parquetPath    string
+	summaryPath    string
+	htmlPath       string
+	svgDir         string
+	gnuplotDir     string
+	overlayPath    string
+	overlaySystems []string
+	overlayASCII   bool
+	format         string
+	workers        int
+	progress       bool
+	strict         bool
+	maxFailures    int
+	stream         bool
+	traceID        string
+	filter         rulebook.ScaleFilter
+	opts           reportOptions
+}
+
+// runTests loads, computes and validates the data, writes the requested
+// outputs and prints the report. It returns the process exit code, or an
+// error when the run could not complete.
+func runTests(cfg runConfig) (int, error) {
+	// Ensure results directory exists
+	if !cfg.dryRun {
+		os.MkdirAll(cfg.testResultsDir, 0755)
+	}
+
+	// Load, compute and validate
+	var traceSteps []string
+	var progress rulebook.ProgressFunc
+	progressShown := false
+	if cfg.progress {
+		progress = func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\rComputed %d/%d scales", done, total)
+			progressShown = true
+		}
+	}
+	run, err := rulebook.ExecutePipeline(rulebook.PipelineConfig{
+		BaseDataPath:    cfg.baseDataPath,
+		TestInputPaths:  cfg.inputPaths,
+		AnswerKeyPath:   cfg.answerKeyPath,
+		Tolerances:      cfg.tolerances,
+		Gates:           cfg.gates,
+		Workers:         cfg.workers,
+		Progress:        progress,
+		RecordTiming:    cfg.opts.timing,
+		Strict:          cfg.strict,
+		MaxFailures:     cfg.maxFailures,
+		StreamAnswerKey: cfg.stream,
+		Filter:          cfg.filter,
+		TraceScaleID:    cfg.traceID,
+		Trace:           func(step string) { traceSteps = append(traceSteps, step) },
+	})
+	if progressShown {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		return 1, err
+	}
+	systemsMap := run.Systems
+	scalesBySystem := run.ScalesBySystem
+	allScales := run.AllScales
+
+	// Save results (test scales only for validation), unless they go to stdout
+	if !cfg.dryRun && cfg.format != formatJSON && cfg.format != formatCSV {
+		if err := rulebook.SaveResults(cfg.resultsPath, run.Results); err != nil {
+			return 1, fmt.Errorf("could not save results: %w", err)
+		}
+		if err := rulebook.SaveResultsCSV(cfg.resultsCSVPath, run.Results); err != nil {
+			return 1, fmt.Errorf("could not save CSV results: %w", err)
+		}
+	}
+
+	// Fill gaps between actual iterations
+	if cfg.interpolate {
+		for systemID, scales := range scalesBySystem {
+			for _, interpolated := range rulebook.InterpolateMissingScales(systemsMap[systemID], scales) {
+				allScales = append(allScales, interpolated.ToOutputMap())
+			}
+		}
+	}
+
+	// Project any requested iterations the data does not already cover
+	if len(cfg.projectIters) > 0 {
+		allScales = appendProjections(allScales, scalesBySystem, systemsMap, cfg.projectIters, cfg.anchor)
+	}
+
+	if cfg.gnuplotDir != "" {
+		if err := rulebook.SaveGnuplotData(cfg.gnuplotDir, groupOutputBySystem(allScales), systemsMap); err != nil {
+			return 1, fmt.Errorf("could not write gnuplot data: %w", err)
+		}
+	}
+	if cfg.svgDir != "" {
+		if err := writeSVGPlots(cfg.svgDir, allScales, scalesBySystem, systemsMap); err != nil {
+			return 1, fmt.Errorf("could not write SVG plots: %w", err)
+		}
+	}
+
+	if cfg.overlayPath != "" {
+		if err := writeOverlayPlot(cfg.overlayPath, allScales, systemsMap, cfg.overlaySystems); err != nil {
+			return 1, fmt.Errorf("could not write overlay plot: %w", err)
+		}
+	}
+
+	if cfg.jsonlPath != "" {
+		if err := rulebook.SaveResultsJSONL(cfg.jsonlPath, run.Results); err != nil {
+			return 1, fmt.Errorf("could not save JSON lines results: %w", err)
+		}
+	}
+	if cfg.parquetPath != "" {
+		if err := rulebook.SaveResultsParquet(cfg.parquetPath, run.Results); err != nil {
+			return 1, fmt.Errorf("could not save Parquet results: %w", err)
+		}
+	}
+	if cfg.junitPath != "" {
+		if err := rulebook.SaveJUnitReport(cfg.junitPath, scaleIDsOf(run.Results.Scales), run.Failures); err != nil {
+			return 1, fmt.Errorf("could not save JUnit report: %w", err)
+		}
+	}
+
+	if cfg.htmlPath != "" {
+		if err := rulebook.SaveHTMLReport(cfg.htmlPath, systemsMap, allScales, run.Failures); err != nil {
+			return 1, fmt.Errorf("could not save HTML report: %w", err)
+		}
+	}
+
+	// Compare against a previous run
+	var comparison []rulebook.ValidationResult
+	if cfg.comparePath != "" {
+		previous, err := rulebook.LoadResults(cfg.comparePath)
+		if err != nil {
+			return 1, fmt.Errorf("could not load comparison results: %w", err)
+		}
+		comparison = rulebook.CompareResults(previous, run.Results, rulebook.Tolerance)
+	}
+
+	// Check against, then refresh, the golden baseline
+	var baselineDrift []rulebook.ValidationResult
+	if cfg.baselineCheck != "" {
+		baseline, err := rulebook.LoadResults(cfg.baselineCheck)
+		if err != nil {
+			return 1, fmt.Errorf("could not load baseline: %w", err)
+		}
+		baselineDrift = rulebook.CompareResults(baseline, run.Results, rulebook.Tolerance)
+	}
+	if cfg.baselineWrite != "" {
+		if err := rulebook.SaveResults(cfg.baselineWrite, run.Results); err != nil {
+			return 1, fmt.Errorf("could not save baseline: %w", err)
+		}
+	}
+
+	opts := cfg.opts
+	opts.computeTimes = run.ComputeTimes
+	opts.stoppedEarly = run.StoppedEarly
+	if cfg.overlayASCII {
+		overlay, err := selectOverlaySystems(allScales, systemsMap, cfg.overlaySystems)
+		if err != nil {
+			return 1, fmt.Errorf("could not build overlay plot: %w", err)
+		}
+		opts.overlay = overlay
+	}
+	for _, s := range run.BaseData.Systems {
+		opts.systemFileOrder = append(opts.systemFileOrder, s.SystemID)
+	}
+
+	// Validate fitted slopes against theoretical slopes
+	slopeResults := rulebook.ValidateSystemSlopes(scalesBySystem, systemsMap, cfg.slopeTolerance)
+	opts.warnings = collectWarnings(systemsMap, allScales, scalesBySystem, opts)
+	if cfg.summaryPath != "" {
+		summary := rulebook.BuildRunSummary(run, slopeResults)
+		summary.Warnings = len(opts.warnings)
+		for i := range summary.Systems {
+			for _, w := range opts.warnings {
+				if w.systemID == summary.Systems[i].SystemID {
+					summary.Systems[i].Warnings++
+				}
+			}
+		}
+		if err := rulebook.SaveRunSummary(cfg.summaryPath, summary); err != nil {
+			return 1, fmt.Errorf("could not save run summary: %w", err)
+		}
+	}
+
+	switch cfg.format {
+	case formatTable:
+		if cfg.onlyFailures {
+			printFailuresOnly(run.PassCount, run.FailCount, run.Failures, slopeResults, run.ComputeErrors, opts)
+		} else {
+			printFullReport(systemsMap, allScales, scalesBySystem, run.PassCount, run.FailCount, run.Failures, slopeResults, run.ComputeErrors, opts)
+		}
+		if cfg.comparePath != "" {
+			printComparison("Comparison vs "+cfg.comparePath, comparison)
+		}
+		if cfg.baselineCheck != "" {
+			printComparison("Baseline Check vs "+cfg.baselineCheck, baselineDrift)
+		}
+		if len(run.Gates) > 0 {
+			printGates(run.Gates, scaleIDsOf(run.Results.Scales))
+		}
+		if len(cfg.measureTargets) > 0 {
+			printMeasureTargets(cfg.measureTargets, systemsMap, scalesBySystem)
+		}
+		if cfg.traceID != "" {
+			printTrace(cfg.traceID, traceSteps)
+		}
+	case formatPlotOnly:
+		printPlotsOnly(systemsMap, allScales, scalesBySystem, opts)
+	case formatJSON:
+		if err := rulebook.WriteResults(os.Stdout, run.Results); err != nil {
+			return 1, fmt.Errorf("could not write results: %w", err)
+		}
+	case formatCSV:
+		if err := rulebook.WriteResultsCSV(os.Stdout, run.Results); err != nil {
+			return 1, fmt.Errorf("could not write CSV results: %w", err)
+		}
+	}
+
+	// Exit with appropriate code
+	if run.StoppedEarly || passRate(run.PassCount, run.FailCount) < cfg.opts.minPassRate || len(run.ComputeErrors) > 0 ||
+		(opts.failOnWarnings && len(opts.warnings) > 0) || len(baselineDrift) > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// watchedFiles returns the data files whose changes trigger a rerun in -watch mode
+func (cfg runConfig) watchedFiles() []string {
+	files := []string{cfg.baseDataPath, cfg.answerKeyPath}
+	for _, gate := range cfg.gates {
+		if gate.Path != cfg.answerKeyPath {
+			files = append(files, gate.Path)
+		}
+	}
+	return append(files, cfg.inputPaths...)
+}
+
+// modTimes returns the modification time of each file; missing files map to the zero time
+func modTimes(paths []string) map[string]time.Time {
+	times := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			times[path] = info.ModTime()
+		}
+	}
+	return times
+}
+
+// watchAndRun runs the tests, then polls the data files and reruns whenever
+// one changes, until interrupted. A run that fails to load (e.g. a file caught
+// mid-write) is retried a few times before its error is shown.
+func watchAndRun(cfg runConfig) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	files := cfg.watchedFiles()
+	for {
+		last := modTimes(files)
+		if isTerminal(os.Stdout) {
+			fmt.Print(clearScreen)
+		}
+		runWithRetry(cfg)
+		fmt.Printf("%sWatching %d file(s) for changes (Ctrl-C to exit)...%s\n", dim, len(files), reset)
+
+		ticker := time.NewTicker(watchPollInterval)
+	poll:
+		for {
+			select {
+			case <-interrupt:
+				ticker.Stop()
+				fmt.Println()
+				return
+			case <-ticker.C:
+				if !reflect.DeepEqual(modTimes(files), last) {
+					break poll
+				}
+			}
+		}
+		ticker.Stop()
+	}
+}
+
+// runWithRetry runs the tests, retrying runs that fail with an error
+func runWithRetry(cfg runConfig) {
+	var err error
+	for attempt := 0; attempt < watchRetries; attempt++ {
+		if _, err = runTests(cfg); err == nil {
+			return
+		}
+		time.Sleep(watchPollInterval)
+	}
+	fmt.Printf("%sError: %v%s\n", red, err, reset)
+}
+
+// appendProjections adds projected scales for iterations missing from each system's data
+func appendProjections(allScales []map[string]interface{}, scalesBySystem map[string][]*rulebook.Scale,
+	systems rulebook.SystemsMap, iterations []int, anchor rulebook.ProjectionAnchor) []map[string]interface{} {
+
+	for systemID, scales := range scalesBySystem {
+		present := make(map[int]bool)
+		for _, s := range scales {
+			present[s.Iteration] = true
+		}
+
+		var missing []int
+		for _, iter := range iterations {
+			if !present[iter] {
+				missing = append(missing, iter)
+			}
+		}
+
+		for _, projected := range rulebook.ProjectScales(systems[systemID], scales, missing, anchor) {
+			allScales = append(allScales, projected.ToOutputMap())
+		}
+	}
+	return allScales
+}
+
+// parseAnswerKeyGates parses -answer-key values of the form PATH or
+// PATH@TOLERANCE into required gates named after the file
+func parseAnswerKeyGates(values []string) ([]rulebook.AnswerKeyGate, error) {
+	gates := make([]rulebook.AnswerKeyGate, 0, len(values))
+	for _, value := range values {
+		gate := rulebook.AnswerKeyGate{Path: value, Required: true}
+		// An @ followed by a path is a URL's user info, not a tolerance
+		at := strings.LastIndex(value, "@")
+		if at >= 0 && !(rulebook.IsURL(value) && strings.Contains(value[at+1:], "/")) {
+			tol, err := strconv.ParseFloat(value[at+1:], 64)
+			if err != nil || tol <= 0 || math.IsInf(tol, 0) {
+				return nil, fmt.Errorf("%q: tolerance must be a positive number", value)
+			}
+			gate.Path, gate.Tolerance = value[:at], tol
+		}
+		gate.Name = strings.TrimSuffix(filepath.Base(gate.Path), filepath.Ext(gate.Path))
+		gates = append(gates, gate)
+	}
+	return gates, nil
+}
+
+// measureTarget is one -predict-measure request
+type measureTarget struct {
+	systemID string
+	measure  float64
+}
+
+// parseMeasureTargets parses SYSTEM=MEASURE values
+func parseMeasureTargets(values []string) ([]measureTarget, error) {
+	targets := make([]measureTarget, 0, len(values))
+	for _, value := range values {
+		systemID, measure, ok := strings.Cut(value, "=")
+		if !ok || systemID == "" {
+			return nil, fmt.Errorf("%q: expected SYSTEM=MEASURE", value)
+		}
+		m, err := strconv.ParseFloat(measure, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", value, err)
+		}
+		targets = append(targets, measureTarget{systemID: systemID, measure: m})
+	}
+	return targets, nil
+}
+
+// parseIntList parses a comma-separated list of integers
+func parseIntList(value string) ([]int, error) {
+	var result []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// scaleIDsOf returns the ScaleID of each output map in order
+func scaleIDsOf(scales []map[string]interface{}) []string {
+	ids := make([]string, 0, len(scales))
+	for _, s := range scales {
+		id, _ := s["ScaleID"].(string)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// groupOutputBySystem groups output maps by their System field
+func groupOutputBySystem(allScales []map[string]interface{}) map[string][]map[string]interface{} {
+	bySystem := make(map[string][]map[string]interface{})
+	for _, scale := range allScales {
+		systemID := scale["System"].(string)
+		bySystem[systemID] = append(bySystem[systemID], scale)
+	}
+	return bySystem
+}
+
+// writeSVGPlots writes one <SystemID>.svg log-log plot per system into dir
+func writeSVGPlots(dir string, allScales []map[string]interface{}, scalesBySystem map[string][]*rulebook.Scale, systems rulebook.SystemsMap) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for systemID, scales := range groupOutputBySystem(allScales) {
+		system, ok := systems[systemID]
+		if !ok {
+			continue
+		}
+		svg := rulebook.RenderSVGPlot(scales, system, fitFor(scalesBySystem[systemID]), svgPlotWidth, svgPlotHeight)
+		if err := os.WriteFile(filepath.Join(dir, systemID+".svg"), []byte(svg), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeOverlayPlot writes one SVG plot overlaying the given systems, or all
+// systems when none are named
+func writeOverlayPlot(path string, allScales []map[string]interface{}, systems rulebook.SystemsMap, systemIDs []string) error {
+	bySystem, err := selectOverlaySystems(allScales, systems, systemIDs)
+	if err != nil {
+		return err
+	}
+	svg := rulebook.RenderOverlayPlot(bySystem, systems, svgPlotWidth, svgPlotHeight)
+	return os.WriteFile(path, []byte(svg), 0644)
+}
+
+// selectOverlaySystems groups output maps by system, keeping only the given
+// systems, or all systems when none are named
+func selectOverlaySystems(allScales []map[string]interface{}, systems rulebook.SystemsMap, systemIDs []string) (map[string][]map[string]interface{}, error) {
+	bySystem := groupOutputBySystem(allScales)
+	if len(systemIDs) == 0 {
+		return bySystem, nil
+	}
+	selected := make(map[string][]map[string]interface{}, len(systemIDs))
+	for _, id := range systemIDs {
+		if _, ok := systems[id]; !ok {
+			return nil, fmt.Errorf("%w: %s", rulebook.ErrUnknownSystem, id)
+		}
+		selected[id] = bySystem[id]
+	}
+	return selected, nil
+}
+
+// colorForSystem returns the ANSI color for a system, hashed from its ID so
+// it is the same on every run, or "" when color output is disabled. Two
+// systems may share a color; their symbols still tell them apart.
+func colorForSystem(systemID string) string {
+	if reset == "" {
+		return ""
+	}
+	h := fnv.New32a()
+	h.Write([]byte(systemID))
+	return overlayPalette[h.Sum32()%uint32(len(overlayPalette))]
+}
+
+// renderASCIIOverlay plots several systems on one ASCII log-log chart with
+// shared bounds. Each system is drawn with its own symbol, in its
+// colorForSystem color, and projected points are dimmed.
+func renderASCIIOverlay(bySystem map[string][]map[string]interface{}, systems rulebook.SystemsMap, width, height int) string {
+	systemIDs := make([]string, 0, len(bySystem))
+	for id := range bySystem {
+		systemIDs = append(systemIDs, id)
+	}
+	sort.Strings(systemIDs)
+
+	pointsBySystem := make(map[string][]rulebook.PlotPoint, len(systemIDs))
+	var allPoints []rulebook.PlotPoint
+	for _, id := range systemIDs {
+		points, _ := rulebook.NormalizePlotPoints(rulebook.ExtractPlotPoints(bySystem[id]), nil)
+		pointsBySystem[id] = points
+		allPoints = append(allPoints, points...)
+	}
+	if len(allPoints) == 0 {
+		return "  (No valid data points)"
+	}
+
+	bounds := rulebook.ComputePlotBounds(allPoints)
+	grid := make([][]string, height)
+	for i := range grid {
+		grid[i] = make([]string, width)
+		for j := range grid[i] {
+			grid[i][j] = " "
+		}
+	}
+
+	for i, id := range systemIDs {
+		color := colorForSystem(id)
+		symbol := overlaySymbols[i%len(overlaySymbols)]
+		for _, p := range pointsBySystem[id] {
+			gx := int((p.X - bounds.XMin) / bounds.XRange() * float64(width-1))
+			gy := height - 1 - int((p.Y-bounds.YMin)/bounds.YRange()*float64(height-1))
+			gx = min(max(gx, 0), width-1)
+			gy = min(max(gy, 0), height-1)
+			style := color
+			if p.IsProjected {
+				style += dim
+			}
+			grid[gy][gx] = style + symbol + reset
+		}
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("  %7.2f ┤", bounds.YMax))
+	for i, row := range grid {
+		prefix := "        │"
+		if i == len(grid)-1 {
+			prefix = fmt.Sprintf("  %7.2f ┤", bounds.YMin)
+		}
+		lines = append(lines, prefix+strings.Join(row, ""))
+	}
+	lines = append(lines, fmt.Sprintf("         └%s", strings.Repeat("─", width)))
+	lines = append(lines, fmt.Sprintf("         %-7.2f%s%7.2f", bounds.XMin, strings.Repeat(" ", max(width-14, 1)), bounds.XMax))
+
+	for i, id := range systemIDs {
+		name := id
+		if system, ok := systems[id]; ok && system.DisplayName != "" {
+			name = system.DisplayName
+		}
+		lines = append(lines, fmt.Sprintf("  %s%s%s %-14s %s", colorForSystem(id), overlaySymbols[i%len(overlaySymbols)], reset, id, name))
+	}
+	if dim != "" {
+		lines = append(lines, fmt.Sprintf("  %sDimmed symbols are projected points%s", dim, reset))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// fitFor returns the log-log fit of a system's actual scales, or nil when it cannot be fitted
+func fitFor(scales []*rulebook.Scale) *rulebook.FitResult {
+	fit, err := rulebook.FitLogLog(scales)
+	if err != nil {
+		return nil
+	}
+	return &fit
+}
+
+// configureTolerance sets the validation tolerance from -tolerance, or from
+// $VERITASIUM_TOLERANCE when the flag was not given
+func configureTolerance(flagValue float64) error {
+	if flagWasSet("tolerance") {
+		return rulebook.SetTolerance(flagValue)
+	}
+	env := os.Getenv("VERITASIUM_TOLERANCE")
+	if env == "" {
+		return nil
+	}
+	tol, err := strconv.ParseFloat(env, 64)
+	if err != nil {
+		return fmt.Errorf("invalid VERITASIUM_TOLERANCE %q: %w", env, err)
+	}
+	if err := rulebook.SetTolerance(tol); err != nil {
+		return fmt.Errorf("invalid VERITASIUM_TOLERANCE: %w", err)
+	}
+	return nil
+}
+
+// flagWasSet reports whether a flag was given explicitly on the command line
+func flagWasSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// isTerminal reports whether f is attached to a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// autoPlotWidth sizes plots to the terminal ($COLUMNS) when stdout is a TTY,
+// and falls back to the fixed default when output is piped or redirected
+func autoPlotWidth() int {
+	if !isTerminal(os.Stdout) {
+		return defaultPlotWidth
+	}
+	columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || columns-plotGutterWidth < defaultPlotWidth {
+		return defaultPlotWidth
+	}
+	return columns - plotGutterWidth
+}
+
+// clampInt raises v to at least min
+func clampInt(v, min int) int {
+	if v < min {
+		return min
+	}
+	return v
+}
+
+// plotGrid is the plain-text cell grid of an ASCII log-log plot, one rune
+// per cell with no ANSI codes, plus what the legend needs to mention
+type plotGrid struct {
+	cells           [][]rune
+	bounds          rulebook.PlotBounds
+	hasInterpolated bool
+	drawBand        bool
+	drawErrorBars   bool
+}
+
+// String returns the grid as plain text, one line per row, for golden files
+func (g plotGrid) String() string {
+	rows := make([]string, len(g.cells))
+	for i, row := range g.cells {
+		rows[i] = string(row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// firstRune returns the rune of a one-rune marker string
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+// buildPlotGrid lays out the points, the theoretical line through the first
+// point and, for a non-nil fit with a standard error, the ±1 SE band on a
+// width × height grid. It is deterministic and color-free, so it can be
+// compared against golden grids; renderASCIIPlot adds colors and axes.
+func buildPlotGrid(points []rulebook.PlotPoint, slope float64, fit *rulebook.FitResult, width, height int) plotGrid {
+	bounds := rulebook.ComputePlotBounds(points)
+	xMin, yMin, yMax := bounds.XMin, bounds.YMin, bounds.YMax
+	xRange, yRange := bounds.XRange(), bounds.YRange()
+	g := plotGrid{bounds: bounds}
+
+	theoretical, band, errorBar := firstRune(plotTheoretical), firstRune(plotBand), firstRune(plotErrorBar)
+
+	// Create grid
+	g.cells = make([][]rune, height)
+	for i := range g.cells {
+		g.cells[i] = make([]rune, width)
+		for j := range g.cells[i] {
+			g.cells[i][j] = ' '
+		}
+	}
+
+	// Map to grid coordinates
+	toGrid := func(x, y float64) (int, int) {
+		gx := int((x - xMin) / xRange * float64(width-1))
+		gy := height - 1 - int((y-yMin)/yRange*float64(height-1))
+		if gx < 0 {
+			gx = 0
+		}
+		if gx >= width {
+			gx = width - 1
+		}
+		if gy < 0 {
+			gy = 0
+		}
+		if gy >= height {
+			gy = height - 1
+		}
+		return gx, gy
+	}
+
+	// Draw theoretical slope line
+	if slope != 0 {
+		x0, y0 := points[0].X, points[0].Y
+		for i := 0; i < width; i++ {
+			x := xMin + (float64(i)/float64(width-1))*xRange
+			y := y0 + slope*(x-x0)
+			if y >= yMin && y <= yMax {
+				gx, gy := toGrid(x, y)
+				if g.cells[gy][gx] == ' ' {
+					g.cells[gy][gx] = theoretical
+				}
+			}
+		}
+	}
+
+	// Shade the ±1 SE band in the cells the theoretical line left empty,
+	// skipping columns where the band is narrower than half a row
+	if fit != nil && fit.HasStdErr() {
+		rowHeight := yRange / float64(height-1)
+		for i := 0; i < width; i++ {
+			x := xMin + (float64(i)/float64(width-1))*xRange
+			lo, hi := fit.SlopeBand(x)
+			if hi-lo < rowHeight/2 {
+				continue
+			}
+			lo, hi = math.Max(lo, yMin), math.Min(hi, yMax)
+			if lo > hi {
+				continue
+			}
+			g.drawBand = true
+			_, top := toGrid(x, hi)
+			_, bottom := toGrid(x, lo)
+			for gy := top; gy <= bottom; gy++ {
+				if g.cells[gy][i] == ' ' {
+					g.cells[gy][i] = band
+				}
+			}
+		}
+	}
+
+	// Replicate error bars, drawn under the points
+	if rulebook.PlotErrorBars {
+		for _, p := range points {
+			if !p.HasErrorBar {
+				continue
+			}
+			g.drawErrorBars = true
+			gx, top := toGrid(p.X, math.Min(p.YHigh, yMax))
+			_, bottom := toGrid(p.X, math.Max(p.YLow, yMin))
+			for gy := top; gy <= bottom; gy++ {
+				if g.cells[gy][gx] == ' ' || g.cells[gy][gx] == theoretical {
+					g.cells[gy][gx] = errorBar
+				}
+			}
+		}
+	}
+
+	// Sort a copy: actual first, then interpolated, then projected (so later kinds overlay)
+	rank := func(p rulebook.PlotPoint) int {
+		switch {
+		case p.IsProjected:
+			return 2
+		case p.IsInterpolated:
+			return 1
+		}
+		return 0
+	}
+	ordered := slices.Clone(points)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank(ordered[i]) < rank(ordered[j])
+	})
+
+	// Plot points
+	for _, p := range ordered {
+		gx, gy := toGrid(p.X, p.Y)
+		switch {
+		case p.IsProjected:
+			g.cells[gy][gx] = firstRune(plotProjected)
+		case p.IsInterpolated:
+			g.cells[gy][gx] = firstRune(plotInterp)
+			g.hasInterpolated = true
+		default:
+			g.cells[gy][gx] = firstRune(plotActual)
+		}
+	}
+	return g
+}
+
+// plotCellColors maps each plot rune to its ANSI color. Points are listed
+// first so they win when a custom marker reuses a line character.
+func plotCellColors() []struct {
+	r     rune
+	color string
+} {
+	return []struct {
+		r     rune
+		color string
+	}{
+		{firstRune(plotActual), green},
+		{firstRune(plotProjected), magenta},
+		{firstRune(plotInterp), cyan},
+		{firstRune(plotTheoretical), dim},
+		{firstRune(plotBand), dim},
+		{firstRune(plotErrorBar), dim},
+	}
+}
+
+// colorizeRow renders one grid row, wrapping each marked cell in its color
+func colorizeRow(row []rune) string {
+	colors := plotCellColors()
+	var b strings.Builder
+	for _, r := range row {
+		color := ""
+		for _, c := range colors {
+			if c.r == r {
+				color = c.color
+				break
+			}
+		}
+		if color == "" || r == ' ' {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(color)
+		b.WriteRune(r)
+		b.WriteString(reset)
+	}
+	return b.String()
+}
+
+// renderASCIIPlot creates an ASCII log-log plot. A non-nil fit with a standard
+// error adds a ±1 SE band around the fitted slope.
+func renderASCIIPlot(scales []map[string]interface{}, system *rulebook.System, fit *rulebook.FitResult, width, height int) string {
+	if len(scales) == 0 {
+		return "  (No data)"
+	}
+
+	points, fit := rulebook.NormalizePlotPoints(rulebook.ExtractPlotPoints(scales), fit)
+	if len(points) == 0 {
+		return "  (No valid data points)"
+	}
+
+	slope := system.TheoreticalLogLogSlope
+	g := buildPlotGrid(points, slope, fit, width, height)
+	xMin, xMax := g.bounds.XMin, g.bounds.XMax
+	yMin, yMax := g.bounds.YMin, g.bounds.YMax
+
+	// Build output
+	var lines []string
+
+	logLabel := system.LogLabel()
+	lines = append(lines, fmt.Sprintf("  %s%s%s", dim, system.WithMeasureUnit(rulebook.PlotAxisLabel(logLabel, "Measure")), reset))
+	lines = append(lines, fmt.Sprintf("  %7.2f ┤", yMax))
+
+	for i, row := range g.cells {
+		prefix := "        │"
+		if i == len(g.cells)-1 {
+			prefix = fmt.Sprintf("  %7.2f ┤", yMin)
+		}
+		lines = append(lines, prefix+colorizeRow(row))
+	}
+
+	lines = append(lines, fmt.Sprintf("         └%s", strings.Repeat("─", width)))
+	labelPadding := width - 14
+	if labelPadding < 1 {
+		labelPadding = 1
+	}
+	lines = append(lines, fmt.Sprintf("         %-7.2f%s%7.2f", xMin, strings.Repeat(" ", labelPadding), xMax))
+	lines = append(lines, fmt.Sprintf("  %s%s%s", dim, center(system.WithScaleUnit(rulebook.PlotAxisLabel(logLabel, "Scale")), width+9), reset))
+	legend := fmt.Sprintf("  %s%s%s Actual   %s%s%s Projected   ", green, plotActual, reset, magenta, plotProjected, reset)
+	if g.hasInterpolated {
+		legend += fmt.Sprintf("%s%s%s Interpolated   ", cyan, plotInterp, reset)
+	}
+	legend += fmt.Sprintf("%s%s%s Theoretical (slope=%.3f)", dim, plotTheoretical, reset, slope)
+	if g.drawBand {
+		legend += fmt.Sprintf("   %s%s%s ±1 SE fit", dim, plotBand, reset)
+	}
+	if g.drawErrorBars {
+		legend += fmt.Sprintf("   %s%s%s ±1σ replicates", dim, plotErrorBar, reset)
+	}
+	lines = append(lines, legend)
+
+	return strings.Join(lines, "\n")
+}
+
+func center(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	padding := (width - len(s)) / 2
+	return strings.Repeat(" ", padding) + s + strings.Repeat(" ", width-len(s)-padding)
+}
+
+// printDimensionConvergence prints the local dimension sequence of a fractal
+// and its extrapolated limit. A drifting sequence is left to the
+// warnConvergence warning.
+func printDimensionConvergence(scales []*rulebook.Scale) {
+	local, err := rulebook.DimensionConvergence(scales)
+	if err != nil {
+		fmt.Printf("  %sLocal dimension:   n/a (%v)%s\n", dim, err, reset)
+		return
+	}
+	steps := make([]string, len(local))
+	for i, d := range local {
+		steps[i] = fmt.Sprintf("%.3f", d)
+	}
+	fmt.Printf("  %sLocal dimension:   %s%s\n", dim, strings.Join(steps, " → "), reset)
+
+	tol := rulebook.DimensionConvergenceTolerance
+	switch n := len(local); {
+	case n < 2:
+		fmt.Printf("  %sConvergence:       n/a (one local estimate)%s\n", dim, reset)
+	case rulebook.DimensionConverged(local, tol):
+		fmt.Printf("  %sConvergence:       converging (last change %.3g ≤ %g)%s\n", dim, math.Abs(local[n-1]-local[n-2]), tol, reset)
+	}
+	if limit, ok := rulebook.RichardsonDimension(local); ok {
+		fmt.Printf("  %sExtrapolated:      %.3f (Aitken Δ² on the last three)%s\n", dim, limit, reset)
+	}
+}
+
+// collectWarnings gathers every warning the report can show, in report
+// order, whatever the output format, so -fail-on-warnings can count them
+func collectWarnings(systems rulebook.SystemsMap, allScales []map[string]interface{},
+	scalesBySystem map[string][]*rulebook.Scale, opts reportOptions) []reportWarning {
+	bySystem := groupOutputBySystem(allScales)
+	systemIDs := make([]string, 0, len(bySystem))
+	for id := range bySystem {
+		systemIDs = append(systemIDs, id)
+	}
+	sortSystemIDs(systemIDs, systems, opts)
+
+	var warnings []reportWarning
+	for _, systemID := range systemIDs {
+		system := systems[systemID]
+		for _, warning := range system.Warnings {
+			warnings = append(warnings, reportWarning{warnMetadata, systemID, "metadata: " + warning})
+		}
+		var classErr *rulebook.DataValidationError
+		if errors.As(rulebook.ValidateSystemClass(system), &classErr) {
+			for _, problem := range classErr.Problems {
+				warnings = append(warnings, reportWarning{warnMetadata, systemID, "metadata: " + problem})
+			}
+		}
+
+		var unreliable []string
+		for _, s := range bySystem[systemID] {
+			if flagged, _ := s["ExtrapolationUnreliable"].(bool); flagged {
+				unreliable = append(unreliable, s["ScaleID"].(string))
+			}
+		}
+		if len(unreliable) > 0 {
+			warnings = append(warnings, reportWarning{warnExtrapolation, systemID,
+				"extrapolation unreliable (Scale near float64 limits, not plotted): " + strings.Join(unreliable, ", ")})
+		}
+
+		if outliers := rulebook.FindSlopeOutliers(scalesBySystem[systemID], system, opts.outlierThreshold); len(outliers) > 0 {
+			warnings = append(warnings, reportWarning{warnOutliers, systemID,
+				fmt.Sprintf("possible outliers (|residual| > %g): %s", opts.outlierThreshold, strings.Join(outliers, ", "))})
+		}
+
+		if opts.convergence && system.Class == rulebook.ClassFractal {
+			local, err := rulebook.DimensionConvergence(scalesBySystem[systemID])
+			tol := rulebook.DimensionConvergenceTolerance
+			if n := len(local); err == nil && n >= 2 && !rulebook.DimensionConverged(local, tol) {
+				warnings = append(warnings, reportWarning{warnConvergence, systemID,
+					fmt.Sprintf("local dimension drifting (last change %.3g > %g); the global fit may not be in the scaling range",
+						math.Abs(local[n-1]-local[n-2]), tol)})
+			}
+		}
+	}
+
+	for _, systemID := range systemIDs {
+		stats, err := rulebook.ComputeSystemStats(scalesBySystem[systemID], systems[systemID])
+		if err == nil && stats.NarrowRange() {
+			warnings = append(warnings, reportWarning{warnNarrowRange, systemID,
+				fmt.Sprintf("%s: actual points span only %.2f decades of Scale (minimum %g); its fitted slope is unreliable",
+					systemID, stats.ScaleDecades, rulebook.MinScaleDecades)})
+		}
+	}
+
+	var monotonicScales []*rulebook.Scale
+	for _, systemID := range systemIDs {
+		monotonicScales = append(monotonicScales, scalesBySystem[systemID]...)
+	}
+	for _, id := range rulebook.ValidateMonotonicScale(monotonicScales) {
+		warnings = append(warnings, reportWarning{warnMonotonicity, "", id + ": Scale does not follow its ScaleFactor direction"})
+	}
+	return warnings
+}
+
+func printSystemTable(scales []map[string]interface{}, system *rulebook.System, fitScales []*rulebook.Scale, opts reportOptions) {
+	icon := "📈"
+	if system != nil && system.Class == rulebook.ClassFractal {
+		icon = "🔺"
+	}
+
+	displayName := system.SystemID
+	if system != nil {
+		displayName = system.DisplayName
+	}
 
-	// Ensure results directory exists
-	os.MkdirAll(testResultsDir, 0755)
+	fmt.Printf("\n%s %s%s%s\n", icon, bold, displayName, reset)
+	fmt.Printf("  %sTheoretical slope: %.3f%s\n", dim, system.TheoreticalLogLogSlope, reset)
+	if fit, err := rulebook.FitLogLog(fitScales); err == nil {
+		stdErr := ""
+		if fit.HasStdErr() {
+			stdErr = fmt.Sprintf(" ± %.3f", fit.SlopeStdErr)
+		}
+		fmt.Printf("  %sFitted slope:      %.3f%s (Δ %+.3f)%s\n", dim, fit.Slope, stdErr, fit.Slope-system.TheoreticalLogLogSlope, reset)
+		fmt.Printf("  %sR²:                %.4f%s\n", dim, fit.RSquared, reset)
+		if fit.Trimmed > 0 {
+			fmt.Printf("  %sFit points:        %d of %d (trimmed %d head, %d tail)%s\n",
+				dim, fit.Points, fit.Points+fit.Trimmed, rulebook.FitTrimHead, rulebook.FitTrimTail, reset)
+		}
+	} else {
+		fmt.Printf("  %sFitted slope:      n/a (%v)%s\n", dim, err, reset)
+		fmt.Printf("  %sR²:                n/a%s\n", dim, reset)
+	}
 
-	// Load base data
-	baseData, err := rulebook.LoadBaseData(baseDataPath)
-	if err != nil {
-		fmt.Printf("%sError: Could not load base-data.json: %v%s\n", red, err, reset)
-		os.Exit(1)
+	if system.Class == rulebook.ClassFractal {
+		if estimated, err := rulebook.EstimateFractalDimension(fitScales); err == nil {
+			fmt.Printf("  %sDimension:         %.3f estimated vs %.3f declared%s\n", dim, estimated, *system.FractalDimension, reset)
+		} else {
+			fmt.Printf("  %sDimension:         n/a (%v)%s\n", dim, err, reset)
+		}
+		if opts.bootstrap > 0 {
+			if lo, hi, median, err := rulebook.BootstrapDimensionCI(fitScales, opts.bootstrap, opts.bootstrapSeed); err == nil {
+				fmt.Printf("  %sDimension %.0f%% CI:  [%.3f, %.3f] (median %.3f, %d resamples)%s\n",
+					dim, rulebook.BootstrapConfidence*100, lo, hi, median, opts.bootstrap, reset)
+			} else {
+				fmt.Printf("  %sDimension %.0f%% CI:  n/a (%v)%s\n", dim, rulebook.BootstrapConfidence*100, err, reset)
+			}
+		}
+		if opts.convergence {
+			printDimensionConvergence(fitScales)
+		}
 	}
 
-	// Load test input
-	testInput, err := rulebook.LoadTestInput(testInputPath)
-	if err != nil {
-		fmt.Printf("%sError: Could not load test-input.json: %v%s\n", red, err, reset)
-		os.Exit(1)
+	if iter, slope1, slope2, found := rulebook.DetectCrossover(fitScales); found {
+		fmt.Printf("  %sCrossover:         at iteration %g (slope %.3f → %.3f)%s\n", dim, iter, slope1, slope2, reset)
 	}
 
-	// Load answer key
-	answerKey, err := rulebook.LoadAnswerKey(answerKeyPath)
-	if err != nil {
-		fmt.Printf("%sError: Could not load answer-key.json: %v%s\n", red, err, reset)
-		os.Exit(1)
+	for _, kind := range []warningKind{warnMetadata, warnConvergence, warnExtrapolation, warnOutliers} {
+		for _, w := range opts.warningsOf(kind, system.SystemID) {
+			fmt.Printf("  %s⚠ %s%s\n", yellow, w.message, reset)
+		}
 	}
 
-	// Build systems map
-	systemsMap := rulebook.BuildSystemsMap(baseData.Systems)
+	// Unit suffixes widen the Measure and Scale columns as needed
+	measureHeader, scaleHeader := system.WithMeasureUnit("Measure"), system.WithScaleUnit("Scale")
+	measureWidth := max(12, utf8.RuneCountInString(measureHeader))
+	scaleWidth := max(14, utf8.RuneCountInString(scaleHeader))
 
-	// Compute derived values for test scales
-	computedTestScales := make([]map[string]interface{}, 0, len(testInput.Scales))
+	fmt.Printf("\n  %4s  %*s  %*s  %10s  %12s  %10s\n", "Iter", measureWidth, measureHeader, scaleWidth, scaleHeader, "LogScale", "LogMeasure", "Type")
+	fmt.Println("  " + strings.Repeat("─", 44+measureWidth+scaleWidth))
 
-	for i := range testInput.Scales {
-		scale := &testInput.Scales[i]
-		scale.CalculateAllFields(systemsMap)
-		computedTestScales = append(computedTestScales, scale.ToOutputMap())
-	}
+	// Sort by iteration
+	sort.Slice(scales, func(i, j int) bool {
+		return rulebook.OutputIteration(scales[i]) < rulebook.OutputIteration(scales[j])
+	})
 
-	// Save results (test scales only for validation)
-	results := &rulebook.TestResults{
-		Platform: "golang",
-		Scales:   computedTestScales,
-	}
+	for _, s := range scales {
+		isProj, _ := s["IsProjected"].(bool)
+		isInterp, _ := s["IsInterpolated"].(bool)
+		color := green
+		marker := plotActual
+		typeLabel := "actual"
+		if isProj {
+			color = magenta
+			marker = plotProjected
+			typeLabel = "projected"
+		} else if isInterp {
+			color = cyan
+			marker = plotInterp
+			typeLabel = "interpolated"
+		}
 
-	err = rulebook.SaveResults(resultsPath, results)
-	if err != nil {
-		fmt.Printf("%sError: Could not save results: %v%s\n", red, err, reset)
-		os.Exit(1)
+		fmt.Printf("  %s%4s  %*s  %*s  %10s  %12s  %s %s%s\n",
+			color,
+			formatIteration(rulebook.OutputIteration(s)),
+			measureWidth, formatMeasure(s["Measure"]),
+			scaleWidth, formatMagnitude(s["Scale"].(float64), 8, 14),
+			formatLogValue(s["LogScale"]),
+			formatLogValue(s["LogMeasure"]),
+			marker,
+			typeLabel,
+			reset)
 	}
 
-	// Merge base scales with computed test scales for full visualization
-	allScales := mergeScales(baseData.Scales, computedTestScales, systemsMap)
+	fmt.Printf("\n  %sRow count: %d%s\n", dim, len(scales), reset)
+}
 
-	// Validate against answer key
-	passCount, failCount, failures := rulebook.ValidateAllScales(computedTestScales, answerKey)
+// formatIteration renders whole iterations as integers and fractional ones with decimals
+func formatIteration(iter float64) string {
+	if iter == math.Trunc(iter) {
+		return strconv.Itoa(int(iter))
+	}
+	return strconv.FormatFloat(iter, 'f', -1, 64)
+}
 
-	// Print full report
-	printFullReport(systemsMap, allScales, passCount, failCount, failures)
+// printComparison prints the differences between a previous results file
+// (a -compare file or a baseline) and this run under the given title
+func printComparison(title string, diffs []rulebook.ValidationResult) {
+	fmt.Printf("%s%s:%s\n", cyan, title, reset)
+	fmt.Println(strings.Repeat("─", 80))
 
-	// Exit with appropriate code
-	if failCount > 0 {
-		os.Exit(1)
+	if len(diffs) == 0 {
+		fmt.Printf("  %s✓ No differences beyond tolerance%s\n", green, reset)
+	} else {
+		fmt.Printf("  %s⚠ %d scale(s) differ%s\n", yellow, len(diffs), reset)
+		for _, diff := range diffs {
+			fmt.Printf("    • %s:\n", diff.ScaleID)
+			for _, m := range diff.Mismatches {
+				fmt.Printf("      - %s\n", m)
+			}
+		}
 	}
+	fmt.Print("================================================================================\n\n")
 }
 
-// mergeScales combines base scales with computed test scales
-func mergeScales(baseScales []rulebook.Scale, testScales []map[string]interface{}, systems rulebook.SystemsMap) []map[string]interface{} {
-	// Convert base scales to maps
-	all := make([]map[string]interface{}, 0, len(baseScales)+len(testScales))
+// systemSortKeys are the accepted -sort-systems values
+var systemSortKeys = []string{"id", "slope", "class", "file"}
 
-	for i := range baseScales {
-		scale := &baseScales[i]
-		scale.CalculateAllFields(systems)
-		all = append(all, scale.ToOutputMap())
+// sortSystemIDs orders system IDs by opts.sortSystems: alphabetically ("id"),
+// by theoretical slope, by class, or by position in base-data.json ("file").
+// Ties, and systems missing from the map or file, fall back to ID order.
+func sortSystemIDs(ids []string, systems rulebook.SystemsMap, opts reportOptions) {
+	sort.Strings(ids)
+
+	var less func(a, b string) bool
+	switch opts.sortSystems {
+	case "slope":
+		less = func(a, b string) bool {
+			sa, oka := systems[a]
+			sb, okb := systems[b]
+			if !oka || !okb {
+				return oka && !okb
+			}
+			return sa.TheoreticalLogLogSlope < sb.TheoreticalLogLogSlope
+		}
+	case "class":
+		class := func(id string) string {
+			if s, ok := systems[id]; ok {
+				return s.Class
+			}
+			return ""
+		}
+		less = func(a, b string) bool { return class(a) < class(b) }
+	case "file":
+		position := func(id string) int {
+			if i := slices.Index(opts.systemFileOrder, id); i >= 0 {
+				return i
+			}
+			return len(opts.systemFileOrder)
+		}
+		less = func(a, b string) bool { return position(a) < position(b) }
+	default:
+		return
 	}
+	sort.SliceStable(ids, func(i, j int) bool { return less(ids[i], ids[j]) })
+}
 
-	// Add test scales
-	all = append(all, testScales...)
+// writeSyntheticInput generates a test input for one system of the base data
+// and writes it to outPath, or to stdout when outPath is empty
+func writeSyntheticInput(baseDataPath, systemID string, iterations int, noise float64, seed int64, outPath string) error {
+	baseData, err := rulebook.LoadBaseData(baseDataPath)
+	if err != nil {
+		return fmt.Errorf("could not load base data: %w", err)
+	}
+	system, ok := rulebook.BuildSystemsMap(baseData.Systems)[systemID]
+	if !ok {
+		return fmt.Errorf("%w: %s", rulebook.ErrUnknownSystem, systemID)
+	}
+	if iterations < 1 || noise < 0 {
+		return fmt.Errorf("-generate needs at least 1 iteration and non-negative noise")
+	}
 
-	return all
+	input := &rulebook.TestInput{
+		Description: fmt.Sprintf("Synthetic %s scales along the theoretical line (noise σ=%g in log units, seed %d)", systemID, noise, seed),
+		Generated:   time.Now().UTC().Format(time.RFC3339),
+		Source:      "run-tests -generate",
+		Scales:      rulebook.GenerateSyntheticScales(system, iterations, noise, seed),
+	}
+	if outPath == "" {
+		return rulebook.WriteTestInput(os.Stdout, input)
+	}
+	return rulebook.SaveTestInput(outPath, input)
 }
 
-// renderASCIIPlot creates an ASCII log-log plot
-func renderASCIIPlot(scales []map[string]interface{}, system *rulebook.System, width, height int) string {
-	if len(scales) == 0 {
-		return "  (No data)"
+// printSystemList loads the base data and prints one row per system, sorted by ID
+func printSystemList(baseDataPath string) error {
+	baseData, err := rulebook.LoadBaseData(baseDataPath)
+	if err != nil {
+		return fmt.Errorf("could not load base data: %w", err)
 	}
 
-	// Extract points
-	type point struct {
-		x, y        float64
-		isProjected bool
+	systems := baseData.Systems
+	sort.Slice(systems, func(i, j int) bool { return systems[i].SystemID < systems[j].SystemID })
+
+	idWidth, nameWidth := len("SystemID"), len("DisplayName")
+	for _, s := range systems {
+		idWidth = max(idWidth, utf8.RuneCountInString(s.SystemID))
+		nameWidth = max(nameWidth, utf8.RuneCountInString(s.DisplayName))
 	}
 
-	var points []point
-	for _, s := range scales {
-		logScale, ok1 := s["LogScale"].(float64)
-		logMeasure, ok2 := s["LogMeasure"].(float64)
-		isProj, _ := s["IsProjected"].(bool)
-		if ok1 && ok2 {
-			points = append(points, point{logScale, logMeasure, isProj})
+	fmt.Printf("%sSystems in %s:%s\n", cyan, baseDataPath, reset)
+	fmt.Printf("%s  %-*s  %-*s  %-10s  %10s  %11s  %8s  %9s%s\n", bold, idWidth, "SystemID", nameWidth, "DisplayName",
+		"Class", "BaseScale", "ScaleFactor", "Slope", "Dimension", reset)
+	fmt.Println("  " + strings.Repeat("─", idWidth+nameWidth+62))
+	for _, s := range systems {
+		dimension := "n/a"
+		if s.FractalDimension != nil {
+			dimension = fmt.Sprintf("%.4f", *s.FractalDimension)
 		}
+		fmt.Printf("  %-*s  %-*s  %-10s  %10g  %11g  %8.4f  %9s\n", idWidth, s.SystemID, nameWidth, s.DisplayName,
+			s.Class, s.BaseScale, s.ScaleFactor, s.TheoreticalLogLogSlope, dimension)
 	}
+	fmt.Printf("\n  %sSystem count: %d%s\n", dim, len(systems), reset)
+	return nil
+}
 
-	if len(points) == 0 {
-		return "  (No valid data points)"
-	}
+// printGates prints the pass counts of each answer-key gate, then the gates
+// each scale passed
+func printGates(gates []rulebook.GateResult, scaleIDs []string) {
+	fmt.Printf("%sAnswer Key Gates:%s\n", cyan, reset)
+	fmt.Println(strings.Repeat("─", 80))
 
-	// Calculate bounds
-	xMin, xMax := points[0].x, points[0].x
-	yMin, yMax := points[0].y, points[0].y
-	for _, p := range points {
-		if p.x < xMin {
-			xMin = p.x
-		}
-		if p.x > xMax {
-			xMax = p.x
+	for _, g := range gates {
+		color, icon := green, "✓"
+		if g.FailCount > 0 {
+			color, icon = yellow, "⚠"
 		}
-		if p.y < yMin {
-			yMin = p.y
+		fmt.Printf("  %s%s %-16s %d passed, %d failed (tolerance %g)%s\n", color, icon, g.Gate.Name,
+			g.PassCount, g.FailCount, g.Gate.EffectiveTolerance(), reset)
+	}
+
+	fmt.Printf("\n  %sGates passed per scale:%s\n", dim, reset)
+	for _, id := range scaleIDs {
+		var passed []string
+		for _, g := range gates {
+			if g.Passed(id) {
+				passed = append(passed, g.Gate.Name)
+			}
 		}
-		if p.y > yMax {
-			yMax = p.y
+		if len(passed) == 0 {
+			fmt.Printf("    %-20s %snone%s\n", id, red, reset)
+			continue
 		}
+		fmt.Printf("    %-20s %s\n", id, strings.Join(passed, ", "))
 	}
+	fmt.Print("================================================================================\n\n")
+}
 
-	xRange := xMax - xMin
-	if xRange == 0 {
-		xRange = 1
-	}
-	yRange := yMax - yMin
-	if yRange == 0 {
-		yRange = 1
-	}
+// printMeasureTargets prints where each system's power law reaches the
+// requested Measure
+func printMeasureTargets(targets []measureTarget, systems rulebook.SystemsMap, scalesBySystem map[string][]*rulebook.Scale) {
+	fmt.Printf("%sMeasure Targets (theoretical line through the last actual point):%s\n", cyan, reset)
+	fmt.Println(strings.Repeat("─", 80))
 
-	// Create grid
-	grid := make([][]string, height)
-	for i := range grid {
-		grid[i] = make([]string, width)
-		for j := range grid[i] {
-			grid[i][j] = " "
+	for _, target := range targets {
+		system, ok := systems[target.systemID]
+		if !ok {
+			fmt.Printf("  %s⚠ %v: %s%s\n", yellow, rulebook.ErrUnknownSystem, target.systemID, reset)
+			continue
 		}
-	}
 
-	// Map to grid coordinates
-	toGrid := func(x, y float64) (int, int) {
-		gx := int((x - xMin) / xRange * float64(width-1))
-		gy := height - 1 - int((y-yMin)/yRange*float64(height-1))
-		if gx < 0 {
-			gx = 0
-		}
-		if gx >= width {
-			gx = width - 1
-		}
-		if gy < 0 {
-			gy = 0
+		var anchor *rulebook.Scale
+		for _, s := range scalesBySystem[target.systemID] {
+			if !s.IsProjected && s.LogScaleValid() && s.LogMeasureValid() &&
+				(anchor == nil || s.EffectiveIteration() > anchor.EffectiveIteration()) {
+				anchor = s
+			}
 		}
-		if gy >= height {
-			gy = height - 1
+
+		scale, iteration, err := rulebook.PredictScaleForMeasure(system, anchor, target.measure)
+		switch {
+		case err != nil && scale == 0:
+			fmt.Printf("  %s⚠ %s: %v%s\n", yellow, target.systemID, err, reset)
+		case err != nil:
+			fmt.Printf("  %-14s Measure %g at Scale %.6g (iteration n/a: %v)\n", target.systemID, target.measure, scale, err)
+		default:
+			fmt.Printf("  %-14s Measure %g at Scale %.6g (iteration %.3f, anchored on %s)\n",
+				target.systemID, target.measure, scale, iteration, anchor.ScaleID)
 		}
-		return gx, gy
 	}
+	fmt.Print("================================================================================\n\n")
+}
 
-	// Draw theoretical slope line
-	slope := system.TheoreticalLogLogSlope
-	if slope != 0 {
-		x0, y0 := points[0].x, points[0].y
-		for i := 0; i < width; i++ {
-			x := xMin + (float64(i)/float64(width-1))*xRange
-			y := y0 + slope*(x-x0)
-			if y >= yMin && y <= yMax {
-				gx, gy := toGrid(x, y)
-				if grid[gy][gx] == " " {
-					grid[gy][gx] = dim + plotTheoretical + reset
-				}
-			}
-		}
+// printTrace prints the calculation steps recorded for one scale
+func printTrace(scaleID string, steps []string) {
+	fmt.Printf("%sCalculation trace for %s:%s\n", cyan, scaleID, reset)
+	fmt.Println(strings.Repeat("─", 80))
+
+	if len(steps) == 0 {
+		fmt.Printf("  %s⚠ No scale with ScaleID %q was computed%s\n", yellow, scaleID, reset)
+	}
+	for _, step := range steps {
+		fmt.Printf("  %s\n", step)
 	}
+	fmt.Print("================================================================================\n\n")
+}
 
-	// Sort: actual first, then projected (so projected overlays)
-	sort.Slice(points, func(i, j int) bool {
-		return !points[i].isProjected && points[j].isProjected
-	})
+// passRate returns the fraction of validated scales that passed, or 1 when
+// nothing was validated
+func passRate(passCount, failCount int) float64 {
+	if passCount+failCount == 0 {
+		return 1
+	}
+	return float64(passCount) / float64(passCount+failCount)
+}
 
-	// Plot points
-	for _, p := range points {
-		gx, gy := toGrid(p.x, p.y)
-		if p.isProjected {
-			grid[gy][gx] = magenta + plotProjected + reset
-		} else {
-			grid[gy][gx] = green + plotActual + reset
+// countFailed returns the number of results that did not pass
+func countFailed(results []rulebook.ValidationResult) int {
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
 		}
 	}
+	return failed
+}
 
-	// Build output
-	var lines []string
+// formatMeasure renders a Measure cell, showing "n/a" for a missing value
+func formatMeasure(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return formatMagnitude(f, 6, 12)
+	}
+	return "n/a"
+}
 
-	lines = append(lines, fmt.Sprintf("  %slog(Measure)%s", dim, reset))
-	lines = append(lines, fmt.Sprintf("  %7.2f ┤", yMax))
+// minFixedSigFigs is the fewest significant digits formatMagnitude accepts
+// in fixed notation before switching to scientific
+const minFixedSigFigs = 4
 
-	for i, row := range grid {
-		prefix := "        │"
-		if i == len(grid)-1 {
-			prefix = fmt.Sprintf("  %7.2f ┤", yMin)
+// formatMagnitude renders v with the given decimals in fixed notation when
+// that shows at least minFixedSigFigs significant digits and fits in width,
+// and otherwise in scientific notation, as short as represents v exactly or
+// with as many digits as fit, so tiny and huge values (1.25e-09, 3.4e+12)
+// stay readable in a fixed-width column
+func formatMagnitude(v float64, decimals, width int) string {
+	fixed := strconv.FormatFloat(v, 'f', decimals, 64)
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return fixed
+	}
+	sigFigs := decimals + int(math.Floor(math.Log10(math.Abs(v)))) + 1
+	if sigFigs >= minFixedSigFigs && len(fixed) <= width {
+		return fixed
+	}
+	if s := strconv.FormatFloat(v, 'e', -1, 64); len(s) <= width {
+		return s
+	}
+	for precision := decimals; precision > 0; precision-- {
+		if s := strconv.FormatFloat(v, 'e', precision, 64); len(s) <= width {
+			mantissa, exponent, _ := strings.Cut(s, "e")
+			return strings.TrimRight(strings.TrimRight(mantissa, "0"), ".") + "e" + exponent
 		}
-		lines = append(lines, prefix+strings.Join(row, ""))
 	}
+	return strconv.FormatFloat(v, 'e', 0, 64)
+}
 
-	lines = append(lines, fmt.Sprintf("         └%s", strings.Repeat("─", width)))
-	lines = append(lines, fmt.Sprintf("         %-7.2f%s%7.2f", xMin, strings.Repeat(" ", width-14), xMax))
-	lines = append(lines, fmt.Sprintf("  %s%s%s", dim, center("log(Scale)", width+9), reset))
-	lines = append(lines, fmt.Sprintf("  %s●%s Actual   %s◌%s Projected   %s·%s Theoretical (slope=%.3f)",
-		green, reset, magenta, reset, dim, reset, slope))
-
-	return strings.Join(lines, "\n")
+// formatLogValue formats a log column, showing "n/a" for logs of non-positive values
+func formatLogValue(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return fmt.Sprintf("%.5f", f)
+	}
+	return "n/a"
 }
 
-func center(s string, width int) string {
-	if len(s) >= width {
-		return s
+// printSystemPlots prints a system's ASCII log-log plot, and its residual
+// plot when requested
+func printSystemPlots(scales []map[string]interface{}, system *rulebook.System, fitScales []*rulebook.Scale, opts reportOptions) {
+	fmt.Printf("\n%s  Log-Log Plot:%s\n", cyan, reset)
+	fmt.Println(renderASCIIPlot(scales, system, fitFor(fitScales), opts.plotWidth, opts.plotHeight))
+
+	if opts.residuals {
+		fmt.Printf("\n%s  Residuals vs Theoretical Line:%s\n", cyan, reset)
+		fmt.Println(rulebook.RenderResidualPlot(scales, system, opts.plotWidth, opts.plotHeight))
 	}
-	padding := (width - len(s)) / 2
-	return strings.Repeat(" ", padding) + s + strings.Repeat(" ", width-len(s)-padding)
 }
 
-func printSystemTable(scales []map[string]interface{}, system *rulebook.System) {
-	icon := "📈"
-	if system != nil && system.Class == "fractal" {
-		icon = "🔺"
+// printPlotsOnly prints just each system's name and plots, for -format plot-only
+func printPlotsOnly(systems rulebook.SystemsMap, allScales []map[string]interface{},
+	scalesBySystem map[string][]*rulebook.Scale, opts reportOptions) {
+	bySystem := groupOutputBySystem(allScales)
+	systemIDs := make([]string, 0, len(bySystem))
+	for id := range bySystem {
+		systemIDs = append(systemIDs, id)
 	}
+	sortSystemIDs(systemIDs, systems, opts)
 
-	displayName := system.SystemID
-	if system != nil {
-		displayName = system.DisplayName
+	for _, systemID := range systemIDs {
+		system := systems[systemID]
+		fmt.Printf("\n%s%s%s (%s)\n", bold, system.DisplayName, reset, systemID)
+		printSystemPlots(bySystem[systemID], system, scalesBySystem[systemID], opts)
 	}
+	if opts.overlay != nil {
+		fmt.Printf("\n%sSystem Overlay (log-log, shared axes):%s\n", cyan, reset)
+		fmt.Println(renderASCIIOverlay(opts.overlay, systems, opts.plotWidth, opts.plotHeight))
+	}
+}
 
-	fmt.Printf("\n%s %s%s%s\n", icon, bold, displayName, reset)
-	fmt.Printf("  %sTheoretical slope: %.3f%s\n", dim, system.TheoreticalLogLogSlope, reset)
+// iterationsLabel describes the iterations of the actual (or projected)
+// output scales: a range such as "0-3" when they are contiguous, otherwise
+// a list such as "2, 5, 11"
+func iterationsLabel(allScales []map[string]interface{}, projected bool) string {
+	seen := make(map[int]bool)
+	for _, s := range allScales {
+		isProj, _ := s["IsProjected"].(bool)
+		isInterp, _ := s["IsInterpolated"].(bool)
+		if iter, ok := s["Iteration"].(int); ok && isProj == projected && !isInterp {
+			seen[iter] = true
+		}
+	}
+	iters := make([]int, 0, len(seen))
+	for iter := range seen {
+		iters = append(iters, iter)
+	}
+	sort.Ints(iters)
 
-	fmt.Printf("\n  %4s  %12s  %14s  %10s  %12s  %10s\n", "Iter", "Measure", "Scale", "LogScale", "LogMeasure", "Type")
-	fmt.Println("  " + strings.Repeat("─", 70))
+	switch {
+	case len(iters) == 0:
+		return "none"
+	case len(iters) == 1:
+		return strconv.Itoa(iters[0])
+	case iters[len(iters)-1]-iters[0] == len(iters)-1:
+		return fmt.Sprintf("%d-%d", iters[0], iters[len(iters)-1])
+	}
+	labels := make([]string, len(iters))
+	for i, iter := range iters {
+		labels[i] = strconv.Itoa(iter)
+	}
+	return strings.Join(labels, ", ")
+}
 
-	// Sort by iteration
-	sort.Slice(scales, func(i, j int) bool {
-		return scales[i]["Iteration"].(int) < scales[j]["Iteration"].(int)
-	})
+// printFailuresOnly prints every validation failure, slope failure and
+// computation error, then a one-line summary, for -only-failures triage
+func printFailuresOnly(passCount, failCount int, failures []rulebook.ValidationResult,
+	slopeResults []rulebook.ValidationResult, computeErrors []error, opts reportOptions) {
 
-	for _, s := range scales {
-		isProj, _ := s["IsProjected"].(bool)
-		color := green
-		marker := "●"
-		typeLabel := "actual"
-		if isProj {
-			color = magenta
-			marker = "◌"
-			typeLabel = "projected"
+	for _, failure := range failures {
+		fmt.Printf("%s✗ %s%s\n", red, failure.ScaleID, reset)
+		for _, m := range failure.Mismatches {
+			fmt.Printf("    - %s\n", m)
 		}
-
-		fmt.Printf("  %s%4d  %12.6f  %14.8f  %10.5f  %12.5f  %s %s%s\n",
-			color,
-			s["Iteration"].(int),
-			s["Measure"].(float64),
-			s["Scale"].(float64),
-			s["LogScale"].(float64),
-			s["LogMeasure"].(float64),
-			marker,
-			typeLabel,
-			reset)
+		if opts.explain && failure.Cause != "" {
+			fmt.Printf("    %s→ %s%s\n", dim, failure.Cause, reset)
+		}
+	}
+	for _, result := range slopeResults {
+		if result.Passed {
+			continue
+		}
+		fmt.Printf("%s✗ %s slope%s\n", red, result.ScaleID, reset)
+		for _, m := range result.Mismatches {
+			fmt.Printf("    - %s\n", m)
+		}
+	}
+	for _, err := range computeErrors {
+		fmt.Printf("%s✗ %v%s\n", red, err, reset)
 	}
 
-	fmt.Printf("\n  %sRow count: %d%s\n", dim, len(scales), reset)
+	rate := passRate(passCount, failCount)
+	color := green
+	if failCount > 0 || len(computeErrors) > 0 || countFailed(slopeResults) > 0 {
+		color = yellow
+	}
+	if rate < opts.minPassRate {
+		color = red
+	}
+	stopped := ""
+	if opts.stoppedEarly {
+		stopped = ", stopped early"
+	}
+	fmt.Printf("%s%d passed, %d failed (%.1f%%%s); %d slope failure(s), %d computation error(s), %d warning(s)%s\n",
+		color, passCount, failCount, rate*100, stopped, countFailed(slopeResults), len(computeErrors), len(opts.warnings), reset)
 }
 
 func printFullReport(systems rulebook.SystemsMap, allScales []map[string]interface{},
-	passCount, failCount int, failures []rulebook.ValidationResult) {
+	scalesBySystem map[string][]*rulebook.Scale, passCount, failCount int, failures []rulebook.ValidationResult,
+	slopeResults []rulebook.ValidationResult, computeErrors []error, opts reportOptions) {
 
 	fmt.Printf("\n%s================================================================================\n", bold)
 	fmt.Printf("  🐹 POWER LAWS & FRACTALS - Go Test Runner%s\n", reset)
 	fmt.Printf("%s================================================================================\n", reset)
 
 	fmt.Printf("\n%sAll Computed Values (from Go):%s\n", cyan, reset)
-	fmt.Printf("  %s●%s Green = Actual Data (iterations 0-3)\n", green, reset)
-	fmt.Printf("  %s◌%s Magenta = Projected/Computed (iterations 4-7)\n", magenta, reset)
+	actualIters, projectedIters := iterationsLabel(allScales, false), iterationsLabel(allScales, true)
+	fmt.Printf("  %s%s%s Green = Actual Data (iterations %s)\n", green, plotActual, reset, actualIters)
+	fmt.Printf("  %s%s%s Magenta = Projected/Computed (iterations %s)\n", magenta, plotProjected, reset, projectedIters)
 	fmt.Println(strings.Repeat("─", 80))
 
 	// Group scales by system
-	bySystem := make(map[string][]map[string]interface{})
-	for _, scale := range allScales {
-		systemID := scale["System"].(string)
-		bySystem[systemID] = append(bySystem[systemID], scale)
-	}
+	bySystem := groupOutputBySystem(allScales)
 
 	// Get sorted system IDs
 	systemIDs := make([]string, 0, len(bySystem))
 	for id := range bySystem {
 		systemIDs = append(systemIDs, id)
 	}
-	sort.Strings(systemIDs)
+	sortSystemIDs(systemIDs, systems, opts)
 
 	for _, systemID := range systemIDs {
 		scales := bySystem[systemID]
 		system := systems[systemID]
 
 		// Print table
-		printSystemTable(scales, system)
+		printSystemTable(scales, system, scalesBySystem[systemID], opts)
+		printSystemPlots(scales, system, scalesBySystem[systemID], opts)
+	}
+
+	if opts.overlay != nil {
+		fmt.Printf("\n%s================================================================================\n", reset)
+		fmt.Printf("%sSystem Overlay (log-log, shared axes):%s\n", cyan, reset)
+		fmt.Println(strings.Repeat("─", 80))
+		fmt.Println(renderASCIIOverlay(opts.overlay, systems, opts.plotWidth, opts.plotHeight))
+	}
 
-		// Print ASCII plot
-		fmt.Printf("\n%s  Log-Log Plot:%s\n", cyan, reset)
-		plot := renderASCIIPlot(scales, system, 50, 12)
-		fmt.Println(plot)
+	if opts.heatmap {
+		fmt.Printf("\n%s================================================================================\n", reset)
+		fmt.Printf("%sResidual Heatmap (|residual| from theoretical line, log-binned):%s\n", cyan, reset)
+		fmt.Println(strings.Repeat("─", 80))
+		fmt.Println(rulebook.RenderResidualHeatmap(scalesBySystem, systems))
 	}
 
 	// Validation results
@@ -377,6 +2024,9 @@ func printFullReport(systems rulebook.SystemsMap, allScales []map[string]interfa
 		fmt.Printf("  %s✓ All %d projected scales validated successfully!%s\n", green, passCount, reset)
 	} else {
 		fmt.Printf("  %s⚠ %d passed, %d failed%s\n", yellow, passCount, failCount, reset)
+		if opts.stoppedEarly {
+			fmt.Printf("  %s✗ stopped after %d failures; the remaining scales were not validated%s\n", red, failCount, reset)
+		}
 		for i, failure := range failures {
 			if i >= 5 {
 				break
@@ -385,6 +2035,72 @@ func printFullReport(systems rulebook.SystemsMap, allScales []map[string]interfa
 			for _, m := range failure.Mismatches {
 				fmt.Printf("      - %s\n", m)
 			}
+			if opts.explain && failure.Cause != "" {
+				fmt.Printf("      %s→ %s%s\n", dim, failure.Cause, reset)
+			}
+		}
+	}
+
+	// Slope validation
+	fmt.Printf("\n%s================================================================================\n", reset)
+	fmt.Printf("%sSlope Validation (fitted actuals vs theoretical slope):%s\n", cyan, reset)
+	fmt.Println(strings.Repeat("─", 80))
+
+	if slopeFailures := countFailed(slopeResults); slopeFailures == 0 {
+		fmt.Printf("  %s✓ All %d system slopes within tolerance%s\n", green, len(slopeResults), reset)
+	} else {
+		fmt.Printf("  %s⚠ %d passed, %d failed%s\n", yellow, len(slopeResults)-slopeFailures, slopeFailures, reset)
+		for _, result := range slopeResults {
+			if result.Passed {
+				continue
+			}
+			fmt.Printf("    • %s:\n", result.ScaleID)
+			for _, m := range result.Mismatches {
+				fmt.Printf("      - %s\n", m)
+			}
+		}
+	}
+
+	// Per-system fit statistics
+	fmt.Printf("\n%s================================================================================\n", reset)
+	fmt.Printf("%sFit Statistics (actual points vs theoretical line, log units):%s\n", cyan, reset)
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("  %-14s  %3s  %10s  %10s  %10s  %10s  %9s  %6s  %7s\n", "System", "N", "Min |r|", "Max |r|", "Mean |r|", "RMS", "χ²/dof", "p", "Decades")
+	for _, systemID := range systemIDs {
+		system := systems[systemID]
+		stats, err := rulebook.ComputeSystemStats(scalesBySystem[systemID], system)
+		if err != nil {
+			fmt.Printf("  %-14s  %s(%v)%s\n", systemID, dim, err, reset)
+			continue
+		}
+		chi := fmt.Sprintf("  %9s  %6s", "n/a", "n/a")
+		if chi2, p, err := rulebook.ChiSquaredVsTheoretical(scalesBySystem[systemID], system); err == nil && stats.Points > 1 {
+			chi = fmt.Sprintf("  %9.3g  %6.3f", chi2/float64(stats.Points-1), p)
+		}
+		fmt.Printf("  %-14s  %3d  %10.6f  %10.6f  %10.6f  %10.6f%s  %7.2f\n", systemID, stats.Points,
+			stats.MinAbsResidual, stats.MaxAbsResidual, stats.MeanAbsResidual, stats.RMSResidual, chi, stats.ScaleDecades)
+	}
+	for _, w := range opts.warningsOf(warnNarrowRange, "") {
+		fmt.Printf("  %s⚠ %s%s\n", yellow, w.message, reset)
+	}
+
+	// Scale monotonicity warnings, shown only when something is out of order
+	if broken := opts.warningsOf(warnMonotonicity, ""); len(broken) > 0 {
+		fmt.Printf("\n%s================================================================================\n", reset)
+		fmt.Printf("%sScale Monotonicity Warnings (check Iteration and IterationFloat):%s\n", yellow, reset)
+		fmt.Println(strings.Repeat("─", 80))
+		for _, w := range broken {
+			fmt.Printf("  %s⚠ %s%s\n", yellow, w.message, reset)
+		}
+	}
+
+	// Computation errors are reported separately from validation mismatches
+	if len(computeErrors) > 0 {
+		fmt.Printf("\n%s================================================================================\n", reset)
+		fmt.Printf("%sComputation Errors (scales that could not be computed):%s\n", red, reset)
+		fmt.Println(strings.Repeat("─", 80))
+		for _, err := range computeErrors {
+			fmt.Printf("  %s✗ %v%s\n", red, err, reset)
 		}
 	}
 
@@ -392,9 +2108,12 @@ func printFullReport(systems rulebook.SystemsMap, allScales []map[string]interfa
 	totalScales := len(allScales)
 	actualCount := 0
 	projectedCount := 0
+	interpolatedCount := 0
 	for _, s := range allScales {
 		if isProj, ok := s["IsProjected"].(bool); ok && isProj {
 			projectedCount++
+		} else if isInterp, ok := s["IsInterpolated"].(bool); ok && isInterp {
+			interpolatedCount++
 		} else {
 			actualCount++
 		}
@@ -403,10 +2122,44 @@ func printFullReport(systems rulebook.SystemsMap, allScales []map[string]interfa
 	fmt.Printf("\n%s================================================================================\n", reset)
 	fmt.Printf("  %sSummary:%s\n", bold, reset)
 	fmt.Printf("    Systems: %d\n", len(bySystem))
-	fmt.Printf("    Total scales: %d (%d per system)\n", totalScales, totalScales/len(bySystem))
-	fmt.Printf("    Actual (0-3): %d\n", actualCount)
-	fmt.Printf("    Projected (4-7): %d\n", projectedCount)
+	if len(bySystem) > 0 {
+		fmt.Printf("    Total scales: %d (%d per system)\n", totalScales, totalScales/len(bySystem))
+	} else {
+		fmt.Printf("    Total scales: %d\n", totalScales)
+	}
+	fmt.Printf("    Actual (%s): %d\n", actualIters, actualCount)
+	fmt.Printf("    Projected (%s): %d\n", projectedIters, projectedCount)
+	if interpolatedCount > 0 {
+		fmt.Printf("    Interpolated: %d\n", interpolatedCount)
+	}
+	rate := passRate(passCount, failCount)
+	rateColor := green
+	if rate < opts.minPassRate {
+		rateColor = red
+	}
+	fmt.Printf("    Pass rate: %s%.1f%%%s (%d/%d validated scales", rateColor, rate*100, reset, passCount, passCount+failCount)
+	if opts.minPassRate < 1 {
+		fmt.Printf(", minimum %.1f%%", opts.minPassRate*100)
+	}
+	if opts.stoppedEarly {
+		fmt.Print(", stopped early")
+	}
+	fmt.Println(")")
+	if opts.failOnWarnings {
+		warnColor := green
+		if len(opts.warnings) > 0 {
+			warnColor = red
+		}
+		fmt.Printf("    Warnings: %s%d%s (-fail-on-warnings)\n", warnColor, len(opts.warnings), reset)
+	}
+	if opts.timing {
+		fmt.Println("    Compute time per system:")
+		for _, systemID := range systemIDs {
+			d := opts.computeTimes[systemID]
+			fmt.Printf("      %-14s %10.3f ms\n", systemID, float64(d)/float64(time.Millisecond))
+		}
+	}
 	fmt.Println("================================================================================")
 	fmt.Printf("  %s✓ Go test run complete!%s\n", green, reset)
-	fmt.Println("================================================================================\n")
+	fmt.Print("================================================================================\n\n")
 }
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"erb-power-laws/pkg/rulebook"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the testdata/*.golden files")
+
+// checkGolden compares got with testdata/<name>.golden, rewriting the file
+// instead when the test runs with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s does not match %s:\n%s", name, path, got)
+	}
+}
+
+// gappySierpinski returns a Sierpinski system and computed actual scales on
+// its theoretical line at the non-contiguous iterations 0, 2 and 5
+func gappySierpinski(t *testing.T) (*rulebook.System, []*rulebook.Scale) {
+	t.Helper()
+	system := &rulebook.System{
+		SystemID:               "Sierpinski",
+		DisplayName:            "Sierpinski Triangle",
+		ScaleFactor:            0.5,
+		BaseScale:              1,
+		TheoreticalLogLogSlope: -1.5849625,
+	}
+	systems := rulebook.BuildSystemsMap([]rulebook.System{*system})
+
+	all := rulebook.GenerateSyntheticScales(system, 6, 0, 1)
+	var actuals []*rulebook.Scale
+	for _, i := range []int{0, 2, 5} {
+		s := all[i]
+		if err := s.CalculateAllFields(systems); err != nil {
+			t.Fatal(err)
+		}
+		actuals = append(actuals, &s)
+	}
+	return system, actuals
+}
+
+// sierpinskiAt returns a computed scale on the system's theoretical line at a
+// possibly fractional iteration
+func sierpinskiAt(t *testing.T, system *rulebook.System, iter float64) *rulebook.Scale {
+	t.Helper()
+	measure := math.Pow(system.BaseScale*system.ScaleFactorPower(iter), system.TheoreticalLogLogSlope)
+	s := &rulebook.Scale{
+		ScaleID:        fmt.Sprintf("%s_%g", system.SystemID, iter),
+		System:         system.SystemID,
+		Iteration:      int(iter),
+		IterationFloat: &iter,
+		Measure:        &measure,
+	}
+	if err := s.CalculateAllFields(rulebook.BuildSystemsMap([]rulebook.System{*system})); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestGappyIterations(t *testing.T) {
+	system, actuals := gappySierpinski(t)
+	slope := system.TheoreticalLogLogSlope
+
+	// Interpolation fills 1, 3 and 4 on the line, using the actual LogScale
+	// positions rather than assuming unit spacing
+	interpolated := rulebook.InterpolateMissingScales(system, actuals)
+	var got []int
+	for _, s := range interpolated {
+		got = append(got, s.Iteration)
+		if want := slope * s.GetLogScale(); math.Abs(s.GetLogMeasure()-want) > 1e-9 {
+			t.Errorf("interpolated iteration %d: LogMeasure %g, want %g", s.Iteration, s.GetLogMeasure(), want)
+		}
+	}
+	if !slices.Equal(got, []int{1, 3, 4}) {
+		t.Fatalf("interpolated iterations %v, want [1 3 4]", got)
+	}
+
+	// A fractional actual at 2.5 does not cover integer iteration 2
+	fractional := []*rulebook.Scale{actuals[0], sierpinskiAt(t, system, 2.5), sierpinskiAt(t, system, 4)}
+	got = nil
+	for _, s := range rulebook.InterpolateMissingScales(system, fractional) {
+		got = append(got, s.Iteration)
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("interpolated iterations around 2.5 %v, want [1 2 3]", got)
+	}
+
+	projected := rulebook.ProjectScales(system, actuals, []int{6, 7}, rulebook.AnchorFirst)
+	if len(projected) != 2 {
+		t.Fatalf("got %d projected scales, want 2", len(projected))
+	}
+	for _, s := range projected {
+		if want := slope * s.GetLogScale(); math.Abs(s.GetLogMeasure()-want) > 1e-9 {
+			t.Errorf("projected iteration %d: LogMeasure %g, want %g", s.Iteration, s.GetLogMeasure(), want)
+		}
+	}
+
+	var outputs []map[string]interface{}
+	for _, group := range [][]*rulebook.Scale{actuals, interpolated, projected} {
+		for _, s := range group {
+			outputs = append(outputs, s.ToOutputMap())
+		}
+	}
+	if label := iterationsLabel(outputs, false); label != "0, 2, 5" {
+		t.Errorf("actual iterations label %q, want %q", label, "0, 2, 5")
+	}
+	if label := iterationsLabel(outputs, true); label != "6-7" {
+		t.Errorf("projected iterations label %q, want %q", label, "6-7")
+	}
+
+	g := buildPlotGrid(rulebook.ExtractPlotPoints(outputs), slope, nil, 50, 12)
+	if !g.hasInterpolated {
+		t.Error("plot grid does not report interpolated points")
+	}
+	counts := make(map[rune]int)
+	for _, row := range g.cells {
+		for _, r := range row {
+			counts[r]++
+		}
+	}
+	for marker, want := range map[string]int{plotActual: 3, plotInterp: 3, plotProjected: 2} {
+		if got := counts[firstRune(marker)]; got != want {
+			t.Errorf("plot has %d %q markers, want %d\n%s", got, marker, want, g)
+		}
+	}
+}
+
+// kochPlotPoints returns plot points for a Koch system with noisy actual
+// scales at iterations 0-3 and projected scales at 4-7, and the fit of the
+// actuals
+func kochPlotPoints(t *testing.T) ([]rulebook.PlotPoint, *rulebook.System, rulebook.FitResult) {
+	t.Helper()
+	system := &rulebook.System{
+		SystemID:               "Koch",
+		DisplayName:            "Koch Snowflake (edge)",
+		ScaleFactor:            1.0 / 3,
+		BaseScale:              1,
+		TheoreticalLogLogSlope: -0.2618595,
+	}
+	systems := rulebook.BuildSystemsMap([]rulebook.System{*system})
+
+	generated := rulebook.GenerateSyntheticScales(system, 4, 0.02, 7)
+	actuals := make([]*rulebook.Scale, len(generated))
+	for i := range generated {
+		if err := generated[i].CalculateAllFields(systems); err != nil {
+			t.Fatal(err)
+		}
+		actuals[i] = &generated[i]
+	}
+	fit, err := rulebook.FitLogLog(actuals)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var outputs []map[string]interface{}
+	for _, s := range actuals {
+		outputs = append(outputs, s.ToOutputMap())
+	}
+	for _, s := range rulebook.ProjectScales(system, actuals, []int{4, 5, 6, 7}, rulebook.AnchorFirst) {
+		outputs = append(outputs, s.ToOutputMap())
+	}
+	return rulebook.ExtractPlotPoints(outputs), system, fit
+}
+
+func TestBuildPlotGridGolden(t *testing.T) {
+	points, system, fit := kochPlotPoints(t)
+	slope := system.TheoreticalLogLogSlope
+
+	checkGolden(t, "plot_koch", buildPlotGrid(points, slope, nil, 50, 12).String())
+
+	g := buildPlotGrid(points, slope, &fit, 50, 12)
+	if !g.drawBand {
+		t.Error("fit with a standard error did not draw the band")
+	}
+	checkGolden(t, "plot_koch_band", g.String())
+
+	checkGolden(t, "plot_koch_small", buildPlotGrid(points, slope, nil, 20, 6).String())
+}